@@ -1,24 +1,38 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log/slog"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/config"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
 	"github.com/terminaldotshop/terminal/go/pkg/tui"
 )
 
 func main() {
+	cfg, err := config.Load("cli", os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	api.Init(cfg)
+
 	log, err := os.Create("output.log")
 	if err != nil {
 		panic(err)
 	}
 	defer log.Close()
-	slog.SetDefault(slog.New(slog.NewTextHandler(log, &slog.HandlerOptions{})))
+	if err := logger.Setup(log, cfg.LogLevel); err != nil {
+		panic(err)
+	}
 
-	model, err := tui.NewModel(lipgloss.DefaultRenderer(), "fingerprint", false, nil, []string{})
+	ctx := logger.WithSession(context.Background(), uuid.NewString())
+	model, err := tui.NewModel(ctx, lipgloss.DefaultRenderer(), "fingerprint", "", false, nil, []string{})
 	if err != nil {
 		panic(err)
 	}
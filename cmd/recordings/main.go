@@ -0,0 +1,138 @@
+// Command recordings lists and replays the asciicast session recordings
+// written by the ssh server when recording_dir is set.
+//
+//	recordings [-dir DIR] list
+//	recordings [-dir DIR] play [-speed 2] [-idle 2s] <session id|file>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/terminaldotshop/terminal/go/pkg/recording"
+)
+
+// restore leaves the alternate screen and shows the cursor, in case playback
+// stops before the recorded session did.
+const restore = "\x1b[?1049l\x1b[?25h\x1b[0m"
+
+func main() {
+	flags := flag.NewFlagSet("recordings", flag.ExitOnError)
+	dir := flags.String("dir", os.Getenv("TERMINAL_RECORDING_DIR"), "recording directory (env TERMINAL_RECORDING_DIR)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: recordings [-dir DIR] list")
+		fmt.Fprintln(flags.Output(), "       recordings [-dir DIR] play [-speed N] [-idle D] <session id|file>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	var err error
+	switch flags.Arg(0) {
+	case "list":
+		err = list(*dir)
+	case "play":
+		err = play(*dir, flags.Args()[1:])
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func list(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("set -dir or TERMINAL_RECORDING_DIR")
+	}
+	infos, err := recording.List(dir)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SESSION\tSTARTED\tDURATION\tSIZE")
+	for _, info := range infos {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\n",
+			info.SessionID,
+			info.Started.Local().Format(time.DateTime),
+			info.Duration.Round(time.Second),
+			formatSize(info.Size),
+		)
+	}
+	return w.Flush()
+}
+
+func play(dir string, args []string) error {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	speed := flags.Float64("speed", 1, "playback speed multiplier")
+	idle := flags.Duration("idle", 2*time.Second, "longest pause between events, 0 for no limit")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: recordings play [-speed N] [-idle D] <session id|file>")
+	}
+	if *speed <= 0 {
+		return fmt.Errorf("speed must be positive")
+	}
+
+	path, err := recording.Find(dir, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	header, events, err := recording.Read(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("replaying %s, recorded at %dx%d, press ctrl+c to stop\n", header.Title, header.Width, header.Height)
+	time.Sleep(time.Second)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer fmt.Print(restore)
+
+	last := 0.0
+	for _, event := range events {
+		wait := time.Duration((event.Time - last) / *speed * float64(time.Second))
+		if *idle > 0 && wait > *idle {
+			wait = *idle
+		}
+		last = event.Time
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-time.After(wait):
+		}
+
+		switch event.Kind {
+		case "o":
+			os.Stdout.WriteString(event.Data)
+		case "r":
+			width, height, _ := strings.Cut(event.Data, "x")
+			// Terminals can't be resized from here, set the title so it's
+			// clear why the replay may look off.
+			fmt.Printf("\x1b]2;recorded at %sx%s\x07", width, height)
+		}
+	}
+	return nil
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/sessions"
+)
+
+const adminUsage = `usage: ssh terminal.shop admin <command>
+
+  sessions                      list live sessions
+  broadcast <message>           show a banner to every session
+  broadcast --clear             remove the banner
+  kick <session id>             disconnect a session, an ID prefix will do
+  maintenance on [--splash] [reason]
+                                pause checkout, optionally with a notice
+                                on the splash screen
+  maintenance off               resume checkout
+  errors [count]                show the latest errors, 20 by default`
+
+// adminHandler runs one admin command, writing its output to w.
+type adminHandler func(w io.Writer, registry *sessions.Registry, args []string) error
+
+var adminCommands = map[string]adminHandler{
+	"sessions":    adminSessions,
+	"broadcast":   adminBroadcast,
+	"kick":        adminKick,
+	"maintenance": adminMaintenance,
+	"errors":      adminErrors,
+}
+
+// adminMiddleware handles `ssh terminal.shop admin ...` for the allow-listed
+// keys. For anyone else admin is just another word passed to the TUI.
+func adminMiddleware(registry *sessions.Registry, keys []string) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			command := s.Command()
+			if len(command) == 0 || command[0] != "admin" {
+				next(s)
+				return
+			}
+
+			anonymous := s.Context().Value("anonymous").(bool)
+			fingerprint := s.Context().Value("fingerprint").(string)
+			if anonymous || !slices.Contains(keys, fingerprint) {
+				next(s)
+				return
+			}
+
+			log := logger.FromContext(logger.WithSession(s.Context(), s.Context().Value("session_id").(string)))
+			log.Info("admin command", "fingerprint", fingerprint, "command", command[1:])
+
+			var handler adminHandler
+			if len(command) > 1 {
+				handler = adminCommands[command[1]]
+			}
+			if handler == nil {
+				wish.Errorln(s, adminUsage)
+				_ = s.Exit(2)
+				return
+			}
+			if err := handler(s, registry, command[2:]); err != nil {
+				wish.Errorln(s, "error: "+err.Error())
+				_ = s.Exit(1)
+				return
+			}
+			_ = s.Exit(0)
+		}
+	}
+}
+
+func adminSessions(w io.Writer, registry *sessions.Registry, args []string) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tFINGERPRINT\tIP\tVIA\tPAGE\tDURATION")
+	for _, session := range registry.List() {
+		via := "ssh"
+		if session.Web {
+			via = "web"
+		}
+		page := session.Page()
+		if page == "" && len(session.Command) > 0 {
+			page = "$ " + strings.Join(session.Command, " ")
+		}
+		fingerprint := session.Fingerprint
+		if session.Anonymous {
+			fingerprint = "anonymous"
+		}
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\t%s\t%s\t%s\n",
+			session.ID,
+			fingerprint,
+			session.IP,
+			via,
+			page,
+			time.Since(session.Started).Round(time.Second),
+		)
+	}
+	return table.Flush()
+}
+
+func adminBroadcast(w io.Writer, registry *sessions.Registry, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: admin broadcast <message> | --clear")
+	}
+	message := strings.Join(args, " ")
+	if message == "--clear" {
+		message = ""
+	}
+
+	registry.SetStatus(func(status *sessions.Status) {
+		status.Banner = message
+	})
+	if message == "" {
+		fmt.Fprintf(w, "cleared the banner for %d sessions\n", len(registry.List()))
+	} else {
+		fmt.Fprintf(w, "sent to %d sessions\n", len(registry.List()))
+	}
+	return nil
+}
+
+func adminKick(w io.Writer, registry *sessions.Registry, args []string) error {
+	if len(args) != 1 || args[0] == "" {
+		return errors.New("usage: admin kick <session id>")
+	}
+
+	matches := registry.Find(args[0])
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no session %q", args[0])
+	case 1:
+		matches[0].Kick()
+		fmt.Fprintf(w, "kicked %s\n", matches[0].ID)
+		return nil
+	default:
+		return fmt.Errorf("%q matches %d sessions, use more of the ID", args[0], len(matches))
+	}
+}
+
+func adminMaintenance(w io.Writer, registry *sessions.Registry, args []string) error {
+	usage := errors.New("usage: admin maintenance on [--splash] [reason] | off")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "on":
+		splash := len(args) > 1 && args[1] == "--splash"
+		if splash {
+			args = args[1:]
+		}
+		reason := strings.Join(args[1:], " ")
+		registry.SetStatus(func(status *sessions.Status) {
+			status.Maintenance = true
+			status.Reason = reason
+			status.Splash = splash
+		})
+	case "off":
+		registry.SetStatus(func(status *sessions.Status) {
+			status.Maintenance = false
+			status.Reason = ""
+			status.Splash = false
+		})
+	default:
+		return usage
+	}
+	fmt.Fprintf(w, "maintenance mode %s\n", args[0])
+	return nil
+}
+
+func adminErrors(w io.Writer, registry *sessions.Registry, args []string) error {
+	count := 20
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return errors.New("usage: admin errors [count]")
+		}
+		count = n
+	}
+
+	entries := logger.RecentErrors()
+	if len(entries) > count {
+		entries = entries[len(entries)-count:]
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "no errors logged since the server started")
+	}
+	for _, entry := range entries {
+		line := entry.Time.UTC().Format(time.RFC3339) + " " + entry.Message
+		for _, attr := range entry.Attrs {
+			line += " " + attr.String()
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/receipt"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandHandler runs a non-interactive command, e.g. `ssh terminal.shop link
+// <code>`, with a client authenticated as the connecting user.
+type commandHandler func(ctx context.Context, s ssh.Session, client *terminal.Client, args []string) error
+
+var commands = map[string]commandHandler{
+	"link":    linkCommand,
+	"claim":   claimCommand,
+	"receipt": receiptCommand,
+}
+
+// commandMiddleware handles the commands above before the session is handed
+// to Bubble Tea, so they work without a PTY and can be piped.
+func commandMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			command := s.Command()
+			if len(command) == 0 {
+				next(s)
+				return
+			}
+
+			handler, ok := commands[strings.ToLower(command[0])]
+			if !ok {
+				next(s)
+				return
+			}
+
+			ctx := logger.WithSession(s.Context(), s.Context().Value("session_id").(string))
+			ctx = trace.ContextWithSpan(ctx, s.Context().Value("span").(trace.Span))
+			client, err := sessionClient(ctx, s)
+			if err == nil {
+				err = handler(ctx, s, client, command[1:])
+			}
+			if err != nil {
+				logger.FromContext(ctx).Error("command failed", "command", command[0], "error", err)
+				wish.Errorln(s, "error: "+api.GetErrorMessage(err))
+				_ = s.Exit(1)
+				return
+			}
+			_ = s.Exit(0)
+		}
+	}
+}
+
+func sessionClient(ctx context.Context, s ssh.Session) (*terminal.Client, error) {
+	fingerprint := s.Context().Value("fingerprint").(string)
+	legacyFingerprint := s.Context().Value("legacy_fingerprint").(string)
+	token, err := api.FetchUserToken(ctx, fingerprint, legacyFingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _, _ := net.SplitHostPort(s.RemoteAddr().String())
+	return api.NewClient(token.AccessToken, &host, nil), nil
+}
+
+func linkCommand(ctx context.Context, s ssh.Session, client *terminal.Client, args []string) error {
+	if s.Context().Value("anonymous").(bool) {
+		return errors.New("ssh public key required to link, see trm.sh/faq")
+	}
+	if len(args) != 1 {
+		return errors.New("usage: ssh terminal.shop link <code>")
+	}
+
+	err := api.LinkKey(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	wish.Println(s, "linked "+s.Context().Value("fingerprint").(string)+" to your terminal account")
+	return nil
+}
+
+func claimCommand(ctx context.Context, s ssh.Session, client *terminal.Client, args []string) error {
+	if s.Context().Value("anonymous").(bool) {
+		return errors.New("run claim with a public key, e.g. ssh -i ~/.ssh/id_ed25519 terminal.shop claim <code>")
+	}
+	if len(args) != 1 {
+		return errors.New("usage: ssh terminal.shop claim <code>")
+	}
+
+	err := api.Claim(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	wish.Println(s, "your cart and profile now belong to "+s.Context().Value("fingerprint").(string))
+	wish.Println(s, "connect with the same key to pick up where you left off")
+	return nil
+}
+
+func receiptCommand(ctx context.Context, s ssh.Session, client *terminal.Client, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: ssh terminal.shop receipt <order-id> [text|markdown|html]")
+	}
+	format := receipt.FormatMarkdown
+	if len(args) == 2 {
+		var err error
+		if format, err = receipt.ParseFormat(args[1]); err != nil {
+			return err
+		}
+	}
+
+	order, err := client.Order.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	products, err := client.Product.List(ctx)
+	if err != nil {
+		return err
+	}
+	return receipt.New(order.Data, products.Data).Render(s, format)
+}
@@ -4,31 +4,41 @@ package main
 // and continually print up to date terminal information.
 
 import (
-	"crypto/md5"
 	_ "embed"
-	"encoding/hex"
+	// the runtime image has no zoneinfo, see tui.WithTimezone
+	_ "time/tzdata"
 
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/muesli/termenv"
-	"github.com/terminaldotshop/terminal/go/pkg/resource"
+	"github.com/pires/go-proxyproto"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/config"
+	"github.com/terminaldotshop/terminal/go/pkg/gateway"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/recording"
+	"github.com/terminaldotshop/terminal/go/pkg/sessions"
+	"github.com/terminaldotshop/terminal/go/pkg/telemetry"
 	"github.com/terminaldotshop/terminal/go/pkg/tui"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
-	"github.com/charmbracelet/wish/logging"
 	"github.com/charmbracelet/wish/recover"
 	gossh "golang.org/x/crypto/ssh"
 )
@@ -43,55 +53,91 @@ const (
 )
 
 func main() {
+	cfg, err := config.Load("ssh", os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := logger.Setup(os.Stderr, cfg.LogLevel); err != nil {
+		slog.Error("Could not configure logging", "error", err)
+		os.Exit(1)
+	}
+	api.Init(cfg)
+
 	ctx, cancel := context.WithCancel(context.Background())
+	shutdownTracing, err := telemetry.Setup(ctx, cfg.TraceExporter, "terminal-ssh")
+	if err != nil {
+		slog.Error("Could not configure tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-signalChan
 		cancel()
 	}()
-	sshPort := os.Getenv("SSH_PORT")
-	httpPort := os.Getenv("HTTP_PORT")
-	if sshPort == "" {
-		sshPort = "2222"
-	}
-	if httpPort == "" {
-		httpPort = "8000"
+	sshPort := cfg.SSHPort
+	httpPort := cfg.HTTPPort
+
+	registry := sessions.NewRegistry()
+
+	hostKey := wish.WithHostKeyPath(cfg.SSHHostKeyPath)
+	if cfg.SSHHostKey != "" {
+		hostKey = wish.WithHostKeyPEM([]byte(cfg.SSHHostKey))
 	}
 
 	s, err := wish.NewServer(
 		wish.WithAddress(net.JoinHostPort("0.0.0.0", sshPort)),
-		wish.WithHostKeyPEM([]byte(resource.Resource.SSHKey.Private)),
+		hostKey,
 		wish.WithMiddleware(
 			recover.Middleware(
-				bubbletea.Middleware(teaHandler),
+				bubbletea.MiddlewareWithProgramHandler(programHandler(registry), termenv.Ascii),
+				recordingMiddleware(cfg.RecordingDir),
 				activeterm.Middleware(), // Bubble Tea apps usually require a PTY.
-				logging.Middleware(),
+				commandMiddleware(),
+				adminMiddleware(registry, cfg.AdminKeyList()),
+				sessionMiddleware(registry),
 			),
 		),
 		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
-			hash := md5.Sum(key.Marshal())
-			fingerprint := hex.EncodeToString(hash[:])
-			ctx.SetValue("fingerprint", fingerprint)
+			ctx.SetValue("fingerprint", api.Fingerprint(key))
+			ctx.SetValue("legacy_fingerprint", api.LegacyFingerprint(key))
 			ctx.SetValue("anonymous", false)
 			return true
 		}),
 		wish.WithKeyboardInteractiveAuth(
 			func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
 				ctx.SetValue("fingerprint", uuid.NewString())
+				ctx.SetValue("legacy_fingerprint", "")
 				ctx.SetValue("anonymous", true)
 				return true
 			},
 		),
 	)
 	if err != nil {
-		log.Error("Could not start server", "error", err)
+		slog.Error("Could not start server", "error", err)
+	}
+
+	if cfg.StatusFile != "" {
+		go registry.WatchStatusFile(ctx, cfg.StatusFile)
 	}
 
-	log.Info("Starting SSH server", "port", sshPort)
+	if cfg.RecordingDir != "" {
+		go pruneRecordings(ctx, cfg.RecordingDir, cfg.RecordingMaxAge())
+	}
+
+	listener, err := listen(s.Addr, cfg.TrustedProxyList())
+	if err != nil {
+		slog.Error("Could not listen", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Starting SSH server", "port", sshPort, "trusted_proxies", cfg.TrustedProxyList())
 	go func() {
-		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
-			log.Error("Could not start server", "error", err)
+		if err = s.Serve(listener); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			slog.Error("Could not start server", "error", err)
 			cancel()
 		}
 	}()
@@ -99,13 +145,22 @@ func main() {
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "https://www.terminal.shop", http.StatusFound)
 	})
+	if cfg.WebCookieSecret != "" {
+		web, err := gateway.New(cfg.WebCookieSecret, cfg.RecordingDir, registry)
+		if err != nil {
+			slog.Error("Could not start browser terminal", "error", err)
+			os.Exit(1)
+		}
+		http.Handle("/terminal/", web.Handler("/terminal"))
+		slog.Info("Serving browser terminal", "path", "/terminal/")
+	}
 
 	// Listen on port 80
 	go func() {
 		defer cancel()
 		err := http.ListenAndServe(":"+httpPort, nil)
 		if err != nil {
-			log.Fatal("ListenAndServe error:", err)
+			slog.Error("ListenAndServe error", "error", err)
 		}
 	}()
 
@@ -114,6 +169,82 @@ func main() {
 	slog.Info("Shutting down server")
 }
 
+// listen opens the SSH listener. When trusted proxies are given, connections
+// from them may start with a PROXY protocol v1 or v2 header, so RemoteAddr, and
+// with it logging and the x-terminal-ip header, is the real client instead of
+// the load balancer. Anyone else sending a header is rejected.
+func listen(addr string, trusted []string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil || len(trusted) == 0 {
+		return listener, err
+	}
+
+	policy, err := proxyproto.StrictWhiteListPolicy(trusted)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &proxyproto.Listener{
+		Listener:          listener,
+		Policy:            policy,
+		ReadHeaderTimeout: 10 * time.Second,
+	}, nil
+}
+
+// sessionMiddleware gives every SSH session an ID, stored in the session
+// context as "session_id", logs its connect and disconnect, and wraps it in a
+// span, stored as "span", that every command of the session is parented to.
+// The session is tracked in registry, and stored as "session", while it lasts.
+func sessionMiddleware(registry *sessions.Registry) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			start := time.Now()
+			sessionID := uuid.NewString()
+			s.Context().SetValue("session_id", sessionID)
+
+			host, _, _ := net.SplitHostPort(s.RemoteAddr().String())
+			session := sessions.New(sessionID, s.Context().Value("fingerprint").(string), host, func() {
+				_ = s.Exit(1)
+				_ = s.Close()
+			})
+			session.Anonymous = s.Context().Value("anonymous").(bool)
+			session.Command = s.Command()
+			registry.Add(session)
+			defer registry.Remove(sessionID)
+			s.Context().SetValue("session", session)
+
+			_, span := telemetry.Start(
+				s.Context(),
+				"ssh.session",
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("session.id", sessionID),
+					attribute.Bool("session.anonymous", s.Context().Value("anonymous").(bool)),
+					attribute.StringSlice("session.command", s.Command()),
+				),
+			)
+			defer span.End()
+			s.Context().SetValue("span", span)
+
+			log := logger.FromContext(logger.WithSession(s.Context(), sessionID))
+			pty, _, _ := s.Pty()
+			log.Info(
+				"connect",
+				"user", s.User(),
+				"remote_addr", s.RemoteAddr().String(),
+				"public_key", s.PublicKey() != nil,
+				"command", s.Command(),
+				"term", pty.Term,
+				"width", pty.Window.Width,
+				"height", pty.Window.Height,
+				"client_version", s.Context().ClientVersion(),
+			)
+			next(s)
+			log.Info("disconnect", "duration", time.Since(start))
+		}
+	}
+}
+
 type sshOutput struct {
 	ssh.Session
 	tty *os.File
@@ -131,6 +262,23 @@ func (s *sshOutput) Fd() uintptr {
 	return s.tty.Fd()
 }
 
+// programHandler runs teaHandler's model and lets the session's registry
+// entry send it messages, starting with the current status.
+func programHandler(registry *sessions.Registry) bubbletea.ProgramHandler {
+	return func(s ssh.Session) *tea.Program {
+		model, opts := teaHandler(s)
+		if model == nil {
+			return nil
+		}
+		program := tea.NewProgram(model, append(opts, bubbletea.MakeOptions(s)...)...)
+
+		session := s.Context().Value("session").(*sessions.Session)
+		session.Attach(s.Context(), func(msg any) { program.Send(msg) })
+		session.Send(registry.Status())
+		return program
+	}
+}
+
 // You can wire any Bubble Tea model up to the middleware with a function that
 // handles the incoming ssh.Session. Here we just grab the terminal info and
 // pass it to the new model. You can also return tea.ProgramOptions (such as
@@ -143,23 +291,51 @@ func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 	}
 	renderer := bubbletea.MakeRenderer(sessionBridge)
 	fingerprint := s.Context().Value("fingerprint").(string)
+	legacyFingerprint := s.Context().Value("legacy_fingerprint").(string)
 	anonymous := s.Context().Value("anonymous").(bool)
+	sessionID := s.Context().Value("session_id").(string)
 	command := s.Command()
-	slog.Info("got fingerprint", "fingerprint", fingerprint)
-	slog.Info("got command", "command", command)
 
 	// Get client IP address from the SSH session
 	clientAddr := s.RemoteAddr().String()
 	host, _, _ := net.SplitHostPort(clientAddr)
-	slog.Info("client connected", "ip", host)
+
+	log := logger.FromContext(logger.WithSession(s.Context(), sessionID))
+	log.Info("starting tui", "fingerprint", fingerprint, "anonymous", anonymous, "ip", host)
 
 	if pty.Term == "xterm-ghostty" {
 		renderer.SetColorProfile(termenv.TrueColor)
 	}
 
-	model, err := tui.NewModel(renderer, fingerprint, anonymous, &host, command)
+	// The session context is cancelled when the client disconnects, which
+	// abandons any requests the TUI still has in flight.
+	ctx := logger.WithSession(s.Context(), sessionID)
+	ctx = trace.ContextWithSpan(ctx, s.Context().Value("span").(trace.Span))
+	ctx = sessions.WithSession(ctx, s.Context().Value("session").(*sessions.Session))
+	ctx = tui.WithTimezone(ctx, sessionTimezone(s))
+	if recorder, ok := s.Context().Value("recorder").(*recording.Recorder); ok {
+		ctx = recording.WithRecorder(ctx, recorder)
+	}
+	model, err := tui.NewModel(ctx, renderer, fingerprint, legacyFingerprint, anonymous, &host, command)
 	if err != nil {
+		log.Error("could not create model", "error", err)
 		return nil, []tea.ProgramOption{}
 	}
 	return model, []tea.ProgramOption{tea.WithAltScreen()}
 }
+
+// sessionTimezone is the TZ the client sent, e.g. with `ssh -o SetEnv=TZ=...`.
+func sessionTimezone(s ssh.Session) string {
+	return sessionEnv(s, "TZ")
+}
+
+// sessionEnv is the value of an environment variable the client sent, empty
+// when it didn't.
+func sessionEnv(s ssh.Session, name string) string {
+	for _, env := range s.Environ() {
+		if value, ok := strings.CutPrefix(env, name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
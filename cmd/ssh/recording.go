@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/recording"
+)
+
+// recordingMiddleware records the TUI sessions that opt in with
+// recording.Env to dir, storing the recorder in the session context as
+// "recorder" so the model can redact card fields and show that it's on.
+func recordingMiddleware(dir string) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if dir == "" || !recording.Requested(sessionEnv(s, recording.Env)) {
+				next(s)
+				return
+			}
+
+			sessionID := s.Context().Value("session_id").(string)
+			log := logger.FromContext(logger.WithSession(s.Context(), sessionID))
+			pty, _, _ := s.Pty()
+			recorder, err := recording.New(dir, sessionID, pty.Window.Width, pty.Window.Height, pty.Term)
+			if err != nil {
+				log.Error("could not start recording", "error", err)
+				next(s)
+				return
+			}
+			defer recorder.Close()
+
+			s.Context().SetValue("recorder", recorder)
+			next(&recordedSession{Session: s, recorder: recorder})
+		}
+	}
+}
+
+// recordedSession tees a session's I/O and window changes to its recorder.
+type recordedSession struct {
+	ssh.Session
+	recorder *recording.Recorder
+	once     sync.Once
+	windows  chan ssh.Window
+}
+
+func (s *recordedSession) Write(p []byte) (int, error) {
+	n, err := s.Session.Write(p)
+	s.recorder.Output(p[:n])
+	return n, err
+}
+
+func (s *recordedSession) Read(p []byte) (int, error) {
+	n, err := s.Session.Read(p)
+	s.recorder.Input(p[:n])
+	return n, err
+}
+
+// Pty records window changes on their way to the Bubble Tea middleware, which
+// reads them from the returned channel.
+func (s *recordedSession) Pty() (ssh.Pty, <-chan ssh.Window, bool) {
+	pty, windows, ok := s.Session.Pty()
+	s.once.Do(func() {
+		s.windows = make(chan ssh.Window, 1)
+		go func() {
+			for {
+				select {
+				case <-s.Context().Done():
+					return
+				case window, ok := <-windows:
+					if !ok {
+						return
+					}
+					s.recorder.Resize(window.Width, window.Height)
+					select {
+					case s.windows <- window:
+					case <-s.Context().Done():
+						return
+					}
+				}
+			}
+		}()
+	})
+	return pty, s.windows, ok
+}
+
+// pruneRecordings deletes recordings older than maxAge now and every hour
+// until ctx is done.
+func pruneRecordings(ctx context.Context, dir string, maxAge time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		deleted, err := recording.Prune(dir, maxAge)
+		if err != nil {
+			slog.Error("Could not prune recordings", "error", err)
+		} else if deleted > 0 {
+			slog.Info("Pruned recordings", "deleted", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,23 +1,37 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/stripe/stripe-go/v78"
 	"github.com/terminaldotshop/terminal-sdk-go"
-	"github.com/terminaldotshop/terminal/go/pkg/resource"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/config"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stripe/stripe-go/v78/token"
 )
 
-func Init() {
-	stripe.Key = resource.Resource.StripePublic.Value
+var cfg *config.Config
+
+// Init configures the package from the loaded config. It must be called
+// before any other function in this package.
+func Init(config *config.Config) {
+	cfg = config
+	stripe.Key = config.StripePublicKey
 }
 
 type FingerprintRequest struct {
@@ -32,39 +46,133 @@ type UserCredentials struct {
 func GetErrorMessage(err error) string {
 	if apiError, ok := err.(*terminal.Error); ok {
 		return strings.Trim(apiError.JSON.ExtraFields["message"].Raw(), "\"")
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		return "the shop is taking too long to respond, try again"
 	} else {
 		return err.Error()
 	}
 }
 
-func FetchUserToken(publicKey string) (*UserCredentials, error) {
+// FetchUserToken exchanges a key fingerprint for user credentials. When a
+// legacy (MD5) fingerprint is given, the auth server moves any account still
+// keyed by it over to the SHA-256 fingerprint.
+func FetchUserToken(ctx context.Context, fingerprint string, legacyFingerprint string) (credentials *UserCredentials, err error) {
+	ctx, span := telemetry.Start(ctx, "FetchUserToken")
+	defer func() { telemetry.End(span, err) }()
+
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", "ssh")
-	data.Set("client_secret", resource.Resource.AuthFingerprintKey.Value)
-	data.Set("fingerprint", publicKey)
+	data.Set("client_secret", cfg.AuthFingerprintKey)
+	data.Set("fingerprint", fingerprint)
+	if legacyFingerprint != "" {
+		data.Set("legacy_fingerprint", legacyFingerprint)
+	}
 	data.Set("provider", "ssh")
-	resp, err := http.PostForm(resource.Resource.Auth.Url+"/token", data)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		cfg.AuthURL+"/token",
+		strings.NewReader(data.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if sessionID := logger.SessionID(ctx); sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		fmt.Println(string(body))
-		return nil, errors.New(fmt.Sprintf("failed to auth: " + string(body)))
+		logger.FromContext(ctx).Error("failed to auth", "status", resp.StatusCode, "body", string(body))
+		return nil, errors.New("failed to auth: " + string(body))
 	}
-	credentials := UserCredentials{}
-	err = json.NewDecoder(resp.Body).Decode(&credentials)
+	credentials = &UserCredentials{}
+	err = json.NewDecoder(resp.Body).Decode(credentials)
 	if err != nil {
 		return nil, err
 	}
-	return &credentials, nil
+	return credentials, nil
+}
+
+// sessionHeader carries the SSH session ID on every request so API logs can be
+// correlated with the session that made them.
+const sessionHeader = "x-terminal-session"
+
+// requestMiddleware tags each SDK request with the session ID carried by its
+// context, wraps it in a client span and logs it.
+func requestMiddleware(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	ctx, span := telemetry.Start(
+		req.Context(),
+		req.Method+" "+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if sessionID := logger.SessionID(ctx); sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+
+	start := time.Now()
+	resp, err := next(req)
+	log := logger.FromContext(ctx).With(
+		"method", req.Method,
+		"path", req.URL.Path,
+		"duration", time.Since(start),
+	)
+	if err != nil {
+		log.Warn("api request failed", "error", err)
+		telemetry.End(span, err)
+		return resp, err
+	}
+	log.Debug("api request", "status", resp.StatusCode)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, err
+}
+
+// NewClient creates a Terminal SDK client authenticated as the given user.
+func NewClient(accessToken string, clientIP *string, region *terminal.Region) *terminal.Client {
+	options := []option.RequestOption{
+		option.WithBaseURL(cfg.APIURL),
+		option.WithBearerToken(accessToken),
+		option.WithAppID("ssh"),
+		// The SDK would retry creates too, retryMiddleware only retries what
+		// is safe to.
+		option.WithMaxRetries(0),
+		option.WithMiddleware(retryMiddleware, requestMiddleware),
+	}
+
+	// Region lookup will be performed server-side
+	if clientIP != nil {
+		options = append(options, option.WithHeader("x-terminal-ip", *clientIP))
+	}
+	if region != nil {
+		options = append(options, option.WithHeader("x-terminal-region", string(*region)))
+	}
+
+	return terminal.NewClient(options...)
 }
 
-func StripeCreditCard(card *stripe.CardParams) (*stripe.Token, *string) {
+func StripeCreditCard(ctx context.Context, card *stripe.CardParams) (*stripe.Token, *string) {
+	ctx, span := telemetry.Start(ctx, "StripeCreditCard")
 	tokenParams := &stripe.TokenParams{Card: card}
+	tokenParams.Context = ctx
 	tokenResult, err := token.New(tokenParams)
+	telemetry.End(span, err)
 
 	if err != nil {
 		error := ""
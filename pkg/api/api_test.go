@@ -6,12 +6,16 @@ import (
 
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal-sdk-go/option"
-	"github.com/terminaldotshop/terminal/go/pkg/resource"
+	"github.com/terminaldotshop/terminal/go/pkg/config"
 )
 
 func TestGetProduct(t *testing.T) {
+	cfg, err := config.Load("test", nil)
+	if err != nil {
+		t.Skipf("no api configured: %s", err)
+	}
 	client := terminal.NewClient(
-		option.WithBaseURL(resource.Resource.Api.Url),
+		option.WithBaseURL(cfg.APIURL),
 	)
 	ctx := context.Background()
 	client.Product.List(ctx)
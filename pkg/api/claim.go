@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// ClaimCode lets an anonymous session hand its cart and profile over to an
+// account keyed by an SSH public key.
+type ClaimCode struct {
+	Code    string `json:"code"`
+	Expires string `json:"expires"`
+}
+
+type claimCodeResponse struct {
+	Data ClaimCode `json:"data"`
+}
+
+type claimParams struct {
+	Code string `json:"code"`
+}
+
+// NewClaimCode creates a one-time code for the current (anonymous) user.
+func NewClaimCode(ctx context.Context, client *terminal.Client) (*ClaimCode, error) {
+	response := claimCodeResponse{}
+	if err := client.Post(ctx, "claim", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// Claim redeems a claim code, moving the anonymous user's cart and profile to
+// the user the client is authenticated as.
+func Claim(ctx context.Context, client *terminal.Client, code string) error {
+	params := claimParams{Code: code}
+	return client.Post(ctx, "claim/redeem", params, nil)
+}
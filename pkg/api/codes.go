@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Kinds of code the cart accepts.
+const (
+	CodePromo    = "promo"
+	CodeGiftCard = "gift_card"
+)
+
+// CartCode is a promo code or gift card applied to the current user's cart.
+type CartCode struct {
+	Code        string `json:"code"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	// Amount is a promo code's discount or a gift card's balance, in cents
+	// (USD).
+	Amount int64 `json:"amount"`
+}
+
+type cartCodeListResponse struct {
+	Data []CartCode `json:"data"`
+}
+
+type cartCodeParams struct {
+	Code string `json:"code"`
+}
+
+// ListCartCodes returns the codes applied to the current user's cart.
+func ListCartCodes(ctx context.Context, client *terminal.Client) ([]CartCode, error) {
+	response := cartCodeListResponse{}
+	if err := client.Get(ctx, "cart/code", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// ApplyCartCode applies a promo code or gift card to the current user's
+// cart. The API rejects codes that are unknown, expired or already used.
+func ApplyCartCode(ctx context.Context, client *terminal.Client, code string) error {
+	params := cartCodeParams{Code: code}
+	return client.Post(ctx, "cart/code", params, nil)
+}
+
+// RemoveCartCode takes a code back off the current user's cart.
+func RemoveCartCode(ctx context.Context, client *terminal.Client, code string) error {
+	if code == "" {
+		return fmt.Errorf("missing required code parameter")
+	}
+	return client.Delete(ctx, "cart/code/"+url.PathEscape(code), nil, nil)
+}
+
+// Totals is what an order costs once its codes are applied, in cents (USD).
+type Totals struct {
+	Subtotal int64
+	Shipping int64
+	// Discount is taken off by promo codes.
+	Discount int64
+	// Tax is the sales tax or VAT the API charges for the address.
+	Tax   int64
+	Total int64
+	// GiftCard is the part of the total paid from gift card balances.
+	GiftCard int64
+	// Due is what's left to charge to the card.
+	Due int64
+}
+
+// NewTotals applies codes to an order. Promo codes come off first, then tax
+// is added, then gift cards pay what they can of the rest, neither taking it
+// below zero. The API works tax out on the discounted price, so it's taken as
+// given.
+func NewTotals(subtotal, shipping, tax int64, codes []CartCode) Totals {
+	totals := Totals{Subtotal: subtotal, Shipping: shipping, Tax: tax}
+	var balance int64
+	for _, code := range codes {
+		switch code.Kind {
+		case CodePromo:
+			totals.Discount += code.Amount
+		case CodeGiftCard:
+			balance += code.Amount
+		}
+	}
+
+	totals.Discount = min(totals.Discount, subtotal+shipping)
+	totals.Total = subtotal + shipping - totals.Discount + tax
+	totals.GiftCard = min(balance, totals.Total)
+	totals.Due = totals.Total - totals.GiftCard
+	return totals
+}
+
+// OrderTotals returns what a placed order cost. The API records what promo
+// codes took off and what gift cards paid as the order's "discount" and
+// "giftCard" amounts, both missing when it had no codes.
+func OrderTotals(order terminal.Order) Totals {
+	field, ok := order.Amount.JSON.ExtraFields["discount"]
+	discount, _ := parseExtra[int64](field, ok)
+	field, ok = order.Amount.JSON.ExtraFields["giftCard"]
+	giftCard, _ := parseExtra[int64](field, ok)
+
+	totals := Totals{
+		Subtotal: order.Amount.Subtotal,
+		Shipping: order.Amount.Shipping,
+		Discount: discount,
+	}
+	if tax := OrderTax(order); tax != nil {
+		totals.Tax = tax.Amount
+	}
+	totals.Total = totals.Subtotal + totals.Shipping - totals.Discount + totals.Tax
+	totals.GiftCard = min(giftCard, totals.Total)
+	totals.Due = totals.Total - totals.GiftCard
+	return totals
+}
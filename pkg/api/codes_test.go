@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestNewTotals(t *testing.T) {
+	promo := func(amount int64) CartCode { return CartCode{Kind: CodePromo, Amount: amount} }
+	gift := func(amount int64) CartCode { return CartCode{Kind: CodeGiftCard, Amount: amount} }
+
+	tests := []struct {
+		name  string
+		codes []CartCode
+		tax   int64
+		want  Totals
+	}{
+		{
+			name: "no codes",
+			want: Totals{Subtotal: 4400, Shipping: 800, Total: 5200, Due: 5200},
+		},
+		{
+			name:  "promo",
+			codes: []CartCode{promo(1000)},
+			want:  Totals{Subtotal: 4400, Shipping: 800, Discount: 1000, Total: 4200, Due: 4200},
+		},
+		{
+			name:  "promo larger than the order",
+			codes: []CartCode{promo(10000)},
+			want:  Totals{Subtotal: 4400, Shipping: 800, Discount: 5200},
+		},
+		{
+			name:  "gift card covers part",
+			codes: []CartCode{promo(200), gift(2500)},
+			want:  Totals{Subtotal: 4400, Shipping: 800, Discount: 200, Total: 5000, GiftCard: 2500, Due: 2500},
+		},
+		{
+			name:  "tax",
+			codes: []CartCode{promo(1000)},
+			tax:   840,
+			want:  Totals{Subtotal: 4400, Shipping: 800, Discount: 1000, Tax: 840, Total: 5040, Due: 5040},
+		},
+		{
+			name:  "gift card pays tax",
+			codes: []CartCode{gift(6000)},
+			tax:   1040,
+			want:  Totals{Subtotal: 4400, Shipping: 800, Tax: 1040, Total: 6240, GiftCard: 6000, Due: 240},
+		},
+		{
+			name:  "gift cards cover all",
+			codes: []CartCode{gift(3000), gift(3000)},
+			want:  Totals{Subtotal: 4400, Shipping: 800, Total: 5200, GiftCard: 5200},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NewTotals(4400, 800, test.tax, test.codes); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOrderTotals(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		want   Totals
+	}{
+		{
+			name:   "no codes",
+			amount: `{"subtotal": 4400, "shipping": 800, "tax": {"kind": "sales", "amount": 420}}`,
+			want:   Totals{Subtotal: 4400, Shipping: 800, Tax: 420, Total: 5620, Due: 5620},
+		},
+		{
+			name:   "promo and gift card",
+			amount: `{"subtotal": 4400, "shipping": 800, "discount": 1000, "giftCard": 2500, "tax": {"kind": "sales", "amount": 340}}`,
+			want:   Totals{Subtotal: 4400, Shipping: 800, Discount: 1000, Tax: 340, Total: 4540, GiftCard: 2500, Due: 2040},
+		},
+		{
+			name:   "gift card paid all",
+			amount: `{"subtotal": 4400, "shipping": 800, "giftCard": 5200}`,
+			want:   Totals{Subtotal: 4400, Shipping: 800, Total: 5200, GiftCard: 5200},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order := terminal.Order{}
+			if err := json.Unmarshal([]byte(`{"amount": `+test.amount+`}`), &order); err != nil {
+				t.Fatal(err)
+			}
+			if got := OrderTotals(order); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
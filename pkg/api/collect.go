@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// CardCollection is card entry in the browser, started by Card.Collect. It
+// recognises the card it creates by ID rather than by the number of cards,
+// so a card removed elsewhere in the meantime can't be mistaken for it.
+type CardCollection struct {
+	URL     string
+	Started time.Time
+	Timeout time.Duration
+	known   map[string]bool
+}
+
+// StartCardCollection notes the cards the user already has and returns a
+// collection with a fresh URL to enter another.
+func StartCardCollection(ctx context.Context, client *terminal.Client) (*CardCollection, error) {
+	cards, err := client.Card.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Card.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &CardCollection{
+		URL:     response.Data.URL,
+		Started: time.Now(),
+		Timeout: cfg.CardCollectMaxWait(),
+		known:   map[string]bool{},
+	}
+	for _, card := range cards.Data {
+		collection.known[card.ID] = true
+	}
+	return collection, nil
+}
+
+// Check returns the card entered in the browser, or nil while there's none
+// yet, along with all of the user's cards.
+func (c *CardCollection) Check(ctx context.Context, client *terminal.Client) (*terminal.Card, []terminal.Card, error) {
+	cards, err := client.Card.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.find(cards.Data), cards.Data, nil
+}
+
+func (c *CardCollection) find(cards []terminal.Card) *terminal.Card {
+	for _, card := range cards {
+		if !c.known[card.ID] {
+			return &card
+		}
+	}
+	return nil
+}
+
+// Expired reports whether the collection has been waiting longer than its
+// timeout, after which the user has to start again.
+func (c *CardCollection) Expired(now time.Time) bool {
+	return now.Sub(c.Started) > c.Timeout
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestCardCollectionFind(t *testing.T) {
+	collection := &CardCollection{known: map[string]bool{"crd_a": true, "crd_b": true}}
+
+	// crd_a was removed elsewhere while the browser was open, which used to
+	// hide the new card from a count of the list.
+	if card := collection.find([]terminal.Card{{ID: "crd_b"}}); card != nil {
+		t.Fatalf("found %q before a card was added", card.ID)
+	}
+	card := collection.find([]terminal.Card{{ID: "crd_b"}, {ID: "crd_c"}})
+	if card == nil || card.ID != "crd_c" {
+		t.Fatalf("expected crd_c, got %v", card)
+	}
+}
+
+func TestCardCollectionExpired(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	collection := &CardCollection{Started: started, Timeout: 10 * time.Minute}
+
+	if collection.Expired(started.Add(9 * time.Minute)) {
+		t.Error("expired before its timeout")
+	}
+	if !collection.Expired(started.Add(11 * time.Minute)) {
+		t.Error("didn't expire after its timeout")
+	}
+}
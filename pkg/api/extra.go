@@ -0,0 +1,23 @@
+package api
+
+import "encoding/json"
+
+// rawField is a field of an API response the SDK doesn't model yet.
+type rawField interface {
+	IsNull() bool
+	Raw() string
+}
+
+// parseExtra decodes a field from a response's ExtraFields, as returned with
+// ok by the map lookup. It's false when the field is missing, null or not a
+// T.
+func parseExtra[T any](field rawField, ok bool) (T, bool) {
+	var value T
+	if !ok || field.IsNull() {
+		return value, false
+	}
+	if err := json.Unmarshal([]byte(field.Raw()), &value); err != nil {
+		return value, false
+	}
+	return value, true
+}
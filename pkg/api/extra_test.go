@@ -0,0 +1,88 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+// raw stands in for an SDK field, its JSON as given.
+type raw string
+
+func (r raw) IsNull() bool { return r == "null" }
+func (r raw) Raw() string  { return string(r) }
+
+func TestParseExtra(t *testing.T) {
+	type card struct {
+		Last4 string `json:"last4"`
+	}
+
+	tests := []struct {
+		name  string
+		field raw
+		ok    bool
+		parse func(raw, bool) (any, bool)
+		want  any
+		found bool
+	}{
+		{
+			name:  "missing",
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[string](r, ok) },
+			want:  "",
+		},
+		{
+			name:  "null",
+			field: "null",
+			ok:    true,
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[string](r, ok) },
+			want:  "",
+		},
+		{
+			name:  "wrong type",
+			field: `"ten"`,
+			ok:    true,
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[int64](r, ok) },
+			want:  int64(0),
+		},
+		{
+			name:  "string",
+			field: `"no rush"`,
+			ok:    true,
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[string](r, ok) },
+			want:  "no rush",
+			found: true,
+		},
+		{
+			name:  "amount",
+			field: `1000`,
+			ok:    true,
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[int64](r, ok) },
+			want:  int64(1000),
+			found: true,
+		},
+		{
+			name:  "struct",
+			field: `{"brand": "visa", "last4": "4242"}`,
+			ok:    true,
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[card](r, ok) },
+			want:  card{Last4: "4242"},
+			found: true,
+		},
+		{
+			name:  "slice",
+			field: `["a", "b"]`,
+			ok:    true,
+			parse: func(r raw, ok bool) (any, bool) { return parseExtra[[]string](r, ok) },
+			want:  []string{"a", "b"},
+			found: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := test.parse(test.field, test.ok)
+			if ok != test.found || !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, %v, want %#v, %v", got, ok, test.want, test.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Gift marks an order as a present. It ships to the recipient's address like
+// any order, with the message on the packing slip and, if asked, no prices.
+type Gift struct {
+	Message    string `json:"message"`
+	HidePrices bool   `json:"hidePrices"`
+}
+
+// SetCartGift makes the current user's cart a gift.
+func SetCartGift(ctx context.Context, client *terminal.Client, gift Gift) error {
+	return client.Put(ctx, "cart/gift", gift, nil)
+}
+
+// RemoveCartGift makes the current user's cart an ordinary order again.
+func RemoveCartGift(ctx context.Context, client *terminal.Client) error {
+	return client.Delete(ctx, "cart/gift", nil, nil)
+}
+
+// CartGift returns the gift details of the current user's cart, or nil if it
+// isn't one.
+func CartGift(cart terminal.Cart) *Gift {
+	field, ok := cart.JSON.ExtraFields["gift"]
+	if gift, ok := parseExtra[Gift](field, ok); ok {
+		return &gift
+	}
+	return nil
+}
+
+// OrderGift returns the gift details of an order, or nil if it isn't one.
+func OrderGift(order terminal.Order) *Gift {
+	field, ok := order.JSON.ExtraFields["gift"]
+	if gift, ok := parseExtra[Gift](field, ok); ok {
+		return &gift
+	}
+	return nil
+}
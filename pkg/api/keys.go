@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Fingerprint returns the OpenSSH SHA-256 fingerprint of a public key, e.g.
+// "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8". This is the identity
+// used for every user that connects with a public key.
+func Fingerprint(key gossh.PublicKey) string {
+	return gossh.FingerprintSHA256(key)
+}
+
+// LegacyFingerprint returns the hex encoded MD5 of the marshaled key, which is
+// how users were identified before SHA-256 fingerprints. It is only sent along
+// so the auth server can migrate existing accounts to the new fingerprint.
+func LegacyFingerprint(key gossh.PublicKey) string {
+	hash := md5.Sum(key.Marshal())
+	return hex.EncodeToString(hash[:])
+}
+
+// Key is an SSH public key linked to a Terminal shop account.
+type Key struct {
+	ID          string `json:"id"`
+	Fingerprint string `json:"fingerprint"`
+	Created     string `json:"created"`
+}
+
+type KeyLinkCode struct {
+	Code    string `json:"code"`
+	Expires string `json:"expires"`
+}
+
+type keyListResponse struct {
+	Data []Key `json:"data"`
+}
+
+type keyLinkCodeResponse struct {
+	Data KeyLinkCode `json:"data"`
+}
+
+type keyLinkParams struct {
+	Code string `json:"code"`
+}
+
+// ListKeys returns every SSH key linked to the current user.
+func ListKeys(ctx context.Context, client *terminal.Client) ([]Key, error) {
+	response := keyListResponse{}
+	if err := client.Get(ctx, "key", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// NewKeyLinkCode creates a one-time code that links another SSH key to the
+// current user when redeemed with `ssh terminal.shop link <code>`.
+func NewKeyLinkCode(ctx context.Context, client *terminal.Client) (*KeyLinkCode, error) {
+	response := keyLinkCodeResponse{}
+	if err := client.Post(ctx, "key/link", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// LinkKey redeems a one-time code, linking the key the client is
+// authenticated with to the account that created the code.
+func LinkKey(ctx context.Context, client *terminal.Client, code string) error {
+	params := keyLinkParams{Code: code}
+	return client.Post(ctx, "key/link/redeem", params, nil)
+}
+
+// DeleteKey unlinks an SSH key from the current user.
+func DeleteKey(ctx context.Context, client *terminal.Client, id string) error {
+	if id == "" {
+		return fmt.Errorf("missing required id parameter")
+	}
+	return client.Delete(ctx, fmt.Sprintf("key/%s", id), nil, nil)
+}
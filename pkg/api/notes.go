@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+)
+
+type cartNoteParams struct {
+	Note string `json:"note"`
+}
+
+// WithDeliveryInstructions stores instructions for the courier with a new
+// address, e.g. where to leave the parcel.
+func WithDeliveryInstructions(instructions string) option.RequestOption {
+	return option.WithJSONSet("instructions", instructions)
+}
+
+// SetCartNote attaches a note for the shop to the current user's cart, which
+// the order keeps. An empty note removes it.
+func SetCartNote(ctx context.Context, client *terminal.Client, note string) error {
+	params := cartNoteParams{Note: note}
+	return client.Put(ctx, "cart/note", params, nil)
+}
+
+// AddressInstructions returns the delivery instructions stored with an
+// address.
+func AddressInstructions(address terminal.Address) string {
+	field, ok := address.JSON.ExtraFields["instructions"]
+	value, _ := parseExtra[string](field, ok)
+	return value
+}
+
+// CartNote returns the note attached to the current user's cart.
+func CartNote(cart terminal.Cart) string {
+	field, ok := cart.JSON.ExtraFields["note"]
+	value, _ := parseExtra[string](field, ok)
+	return value
+}
+
+// OrderNote returns the note the order was placed with.
+func OrderNote(order terminal.Order) string {
+	field, ok := order.JSON.ExtraFields["note"]
+	value, _ := parseExtra[string](field, ok)
+	return value
+}
+
+// OrderInstructions returns the delivery instructions of the address the
+// order ships to, as they were when it was placed.
+func OrderInstructions(order terminal.Order) string {
+	field, ok := order.Shipping.JSON.ExtraFields["instructions"]
+	value, _ := parseExtra[string](field, ok)
+	return value
+}
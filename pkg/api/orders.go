@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+)
+
+// OrderStatusURL is the shop's page for following an order, from the
+// order_status_url setting. It's empty when none is configured.
+func OrderStatusURL(orderID string) string {
+	if cfg == nil || cfg.OrderStatusURL == "" {
+		return ""
+	}
+	return strings.ReplaceAll(cfg.OrderStatusURL, "{id}", url.PathEscape(orderID))
+}
+
+// OrderCard is the card an order was paid with.
+type OrderCard struct {
+	Brand string `json:"brand"`
+	Last4 string `json:"last4"`
+}
+
+// OrderPayment returns the card an order was paid with, or nil if it was paid
+// in full by gift card.
+func OrderPayment(order terminal.Order) *OrderCard {
+	field, ok := order.JSON.ExtraFields["card"]
+	if card, ok := parseExtra[OrderCard](field, ok); ok {
+		return &card
+	}
+	return nil
+}
+
+// Order statuses to filter by, from the order's tracking.
+const (
+	OrderPending   = "pending"
+	OrderShipped   = "shipped"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+)
+
+// OrderStatus sums up where an order is: pending until the carrier has it,
+// then shipped until it's delivered, unless it was cancelled first.
+func OrderStatus(order terminal.Order) string {
+	field, ok := order.JSON.ExtraFields["cancelled"]
+	if cancelled, _ := parseExtra[string](field, ok); cancelled != "" {
+		return OrderCancelled
+	}
+	switch strings.ToUpper(order.Tracking.Status) {
+	case "", "UNKNOWN", "PRE_TRANSIT":
+		return OrderPending
+	case "DELIVERED":
+		return OrderDelivered
+	default:
+		return OrderShipped
+	}
+}
+
+// Orders can be sorted by when they were placed or what they cost, newest or
+// most expensive first unless ascending.
+const (
+	SortByDate   = "created"
+	SortByAmount = "amount"
+)
+
+// OrderQuery selects a page of the current user's orders. Zero values don't
+// filter.
+type OrderQuery struct {
+	Sort      string
+	Ascending bool
+	Status    string
+	// ProductVariantID keeps orders with an item of this variant.
+	ProductVariantID string
+	// From and Before bound when the order was placed, From inclusive and
+	// Before exclusive. They're sent with their offset, so the customer's
+	// days can start at their own midnight.
+	From   time.Time
+	Before time.Time
+	// Cursor continues from the end of a previous page.
+	Cursor string
+	Limit  int
+}
+
+// Values encodes the query as URL query parameters.
+func (q OrderQuery) Values() url.Values {
+	values := url.Values{}
+	if q.Sort != "" {
+		sort := q.Sort
+		if !q.Ascending {
+			sort = "-" + sort
+		}
+		values.Set("sort", sort)
+	}
+	if q.Status != "" {
+		values.Set("status", q.Status)
+	}
+	if q.ProductVariantID != "" {
+		values.Set("productVariantID", q.ProductVariantID)
+	}
+	if !q.From.IsZero() {
+		values.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.Before.IsZero() {
+		values.Set("before", q.Before.Format(time.RFC3339))
+	}
+	if q.Cursor != "" {
+		values.Set("cursor", q.Cursor)
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+// OrderPage is a page of orders. Next is the cursor of the following page,
+// empty on the last.
+type OrderPage struct {
+	Orders []terminal.Order
+	Next   string
+}
+
+// ListOrders returns the page of the current user's orders that query
+// selects.
+func ListOrders(ctx context.Context, client *terminal.Client, query OrderQuery) (OrderPage, error) {
+	opts := []option.RequestOption{}
+	for key, values := range query.Values() {
+		opts = append(opts, option.WithQuery(key, values[0]))
+	}
+	response, err := client.Order.List(ctx, opts...)
+	if err != nil {
+		return OrderPage{}, err
+	}
+	field, ok := response.JSON.ExtraFields["next"]
+	next, _ := parseExtra[string](field, ok)
+	return OrderPage{Orders: response.Data, Next: next}, nil
+}
+
+// WithoutOrders asks View.Init to leave the current user's orders out, which
+// are paged through with ListOrders instead.
+func WithoutOrders() option.RequestOption {
+	return option.WithQuery("orders", "false")
+}
+
+// allOrdersPageSize is how many orders ListAllOrders asks for at a time.
+const allOrdersPageSize = 100
+
+// ListAllOrders pages through every order the current user has placed. It
+// takes a request per hundred orders, so it's only for views that sum up all
+// of them.
+func ListAllOrders(ctx context.Context, client *terminal.Client) ([]terminal.Order, error) {
+	orders := []terminal.Order{}
+	query := OrderQuery{Limit: allOrdersPageSize}
+	for {
+		page, err := ListOrders(ctx, client, query)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page.Orders...)
+		if page.Next == "" {
+			return orders, nil
+		}
+		query.Cursor = page.Next
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestOrderQueryValues(t *testing.T) {
+	query := OrderQuery{
+		Sort:   SortByAmount,
+		Status: OrderShipped,
+		From:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.FixedZone("", -5*60*60)),
+		Before: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Cursor: "ord_9",
+		Limit:  20,
+	}
+	want := "before=2026-02-01T00%3A00%3A00Z&cursor=ord_9&from=2026-01-01T00%3A00%3A00-05%3A00&limit=20&sort=-amount&status=shipped"
+	if got := query.Values().Encode(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	query = OrderQuery{Sort: SortByDate, Ascending: true}
+	if got := query.Values().Encode(); got != "sort=created" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestOrderStatus(t *testing.T) {
+	tests := map[string]string{
+		"":            OrderPending,
+		"PRE_TRANSIT": OrderPending,
+		"TRANSIT":     OrderShipped,
+		"DELIVERED":   OrderDelivered,
+		"RETURNED":    OrderShipped,
+	}
+	for status, want := range tests {
+		order := terminal.Order{Tracking: terminal.OrderTracking{Status: status}}
+		if got := OrderStatus(order); got != want {
+			t.Errorf("status %q: got %s, want %s", status, got, want)
+		}
+	}
+
+	order := terminal.Order{}
+	if err := json.Unmarshal([]byte(`{"id":"ord_1","cancelled":"2026-10-01T12:00:00Z"}`), &order); err != nil {
+		t.Fatal(err)
+	}
+	if got := OrderStatus(order); got != OrderCancelled {
+		t.Errorf("got %s for a cancelled order", got)
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// ShippingRate is one way the current user's cart can ship to its address.
+type ShippingRate struct {
+	ID      string `json:"id"`
+	Service string `json:"service"`
+	// Timeframe is the carrier's delivery estimate, e.g. "3-5 business days".
+	Timeframe string `json:"timeframe"`
+	// Amount is what shipping costs with this rate, in cents (USD).
+	Amount int64 `json:"amount"`
+}
+
+type shippingRateListResponse struct {
+	Data []ShippingRate `json:"data"`
+}
+
+type shippingRateParams struct {
+	RateID string `json:"rateID"`
+}
+
+// ListShippingRates returns the rates available for the address set on the
+// current user's cart.
+func ListShippingRates(ctx context.Context, client *terminal.Client) ([]ShippingRate, error) {
+	response := shippingRateListResponse{}
+	if err := client.Get(ctx, "cart/shipping", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// SetShippingRate ships the current user's cart with rateID, which reprices
+// its shipping.
+func SetShippingRate(ctx context.Context, client *terminal.Client, rateID string) error {
+	if rateID == "" {
+		return fmt.Errorf("missing required rateID parameter")
+	}
+	params := shippingRateParams{RateID: rateID}
+	return client.Put(ctx, "cart/shipping", params, nil)
+}
+
+// CurrentRate returns the index of the rate the cart ships with, matched by
+// service name, or 0 when none match.
+func CurrentRate(rates []ShippingRate, shipping terminal.CartShipping) int {
+	for i, rate := range rates {
+		if strings.EqualFold(rate.Service, shipping.Service) {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestCurrentRate(t *testing.T) {
+	rates := []ShippingRate{
+		{ID: "rate_1", Service: "USPS Ground Advantage", Amount: 500},
+		{ID: "rate_2", Service: "USPS Priority Mail", Amount: 1200},
+	}
+	if got := CurrentRate(rates, terminal.CartShipping{Service: "usps priority mail"}); got != 1 {
+		t.Errorf("got rate %d, want 1", got)
+	}
+	if got := CurrentRate(rates, terminal.CartShipping{Service: "UPS Ground"}); got != 0 {
+		t.Errorf("got rate %d for an unknown service, want 0", got)
+	}
+	if got := CurrentRate(nil, terminal.CartShipping{}); got != 0 {
+		t.Errorf("got rate %d without rates, want 0", got)
+	}
+}
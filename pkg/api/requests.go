@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Kinds of request a customer can make about a delivered order.
+const (
+	RequestReturn  = "return"
+	RequestProblem = "problem"
+)
+
+// Reasons for a return or problem report.
+const (
+	ReasonDamaged   = "damaged"
+	ReasonMissing   = "missing"
+	ReasonWrongItem = "wrong_item"
+	ReasonUnwanted  = "unwanted"
+	ReasonOther     = "other"
+)
+
+// OrderRequestItem is an item of an order a request is about.
+type OrderRequestItem struct {
+	OrderItemID string `json:"orderItemID"`
+	Quantity    int64  `json:"quantity"`
+}
+
+// OrderRequestParams starts a return or reports a problem with an order.
+type OrderRequestParams struct {
+	Kind    string             `json:"kind"`
+	Reason  string             `json:"reason"`
+	Details string             `json:"details,omitempty"`
+	Items   []OrderRequestItem `json:"items"`
+}
+
+// OrderRequest is a return or problem report as the shop is handling it.
+type OrderRequest struct {
+	ID      string             `json:"id"`
+	Kind    string             `json:"kind"`
+	Reason  string             `json:"reason"`
+	Details string             `json:"details"`
+	Items   []OrderRequestItem `json:"items"`
+	// Status is e.g. "open", "approved", "rejected" or "refunded".
+	Status  string `json:"status"`
+	Created string `json:"created"`
+}
+
+// CanCancel is true until an order ships.
+func CanCancel(order terminal.Order) bool {
+	return OrderStatus(order) == OrderPending
+}
+
+// CanRequest is true once an order is delivered, when it can be returned or
+// a problem reported.
+func CanRequest(order terminal.Order) bool {
+	return OrderStatus(order) == OrderDelivered
+}
+
+// CancelOrder cancels an order that hasn't shipped yet and refunds it.
+func CancelOrder(ctx context.Context, client *terminal.Client, orderID string) error {
+	if orderID == "" {
+		return fmt.Errorf("missing required orderID parameter")
+	}
+	return client.Post(ctx, "order/"+url.PathEscape(orderID)+"/cancel", nil, nil)
+}
+
+// NewOrderRequest starts a return or reports a problem with a delivered
+// order.
+func NewOrderRequest(ctx context.Context, client *terminal.Client, orderID string, params OrderRequestParams) error {
+	if orderID == "" {
+		return fmt.Errorf("missing required orderID parameter")
+	}
+	return client.Post(ctx, "order/"+url.PathEscape(orderID)+"/request", params, nil)
+}
+
+// OrderRequests returns the returns and problem reports made about an order,
+// oldest first.
+func OrderRequests(order terminal.Order) []OrderRequest {
+	field, ok := order.JSON.ExtraFields["requests"]
+	requests, _ := parseExtra[[]OrderRequest](field, ok)
+	return requests
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestCanCancelOrRequest(t *testing.T) {
+	tests := []struct {
+		tracking string
+		cancel   bool
+		request  bool
+	}{
+		{tracking: "", cancel: true},
+		{tracking: "PRE_TRANSIT", cancel: true},
+		{tracking: "TRANSIT"},
+		{tracking: "DELIVERED", request: true},
+		{tracking: "RETURNED"},
+	}
+	for _, test := range tests {
+		order := terminal.Order{Tracking: terminal.OrderTracking{Status: test.tracking}}
+		if got := CanCancel(order); got != test.cancel {
+			t.Errorf("CanCancel with tracking %q = %v", test.tracking, got)
+		}
+		if got := CanRequest(order); got != test.request {
+			t.Errorf("CanRequest with tracking %q = %v", test.tracking, got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+)
+
+// idempotencyHeader lets the API recognise a repeated create, e.g. an order
+// retried after a timeout, and answer with the original result instead of
+// creating it twice.
+const idempotencyHeader = "Idempotency-Key"
+
+// maxAttempts counts the first try, so a request is retried at most three
+// times.
+const maxAttempts = 4
+
+var (
+	baseDelay = 250 * time.Millisecond
+	maxDelay  = 4 * time.Second
+)
+
+// Retry describes a failed attempt that is about to be retried.
+type Retry struct {
+	Attempt     int
+	MaxAttempts int
+	Wait        time.Duration
+	Err         error
+}
+
+type retryObserverKey struct{}
+
+// WithRetryObserver returns a copy of ctx that calls fn before each retry of
+// a request made with it, so the UI can show that it's retrying.
+func WithRetryObserver(ctx context.Context, fn func(Retry)) context.Context {
+	return context.WithValue(ctx, retryObserverKey{}, fn)
+}
+
+// WithIdempotencyKey marks a create as safe to retry. Use the same key for
+// every attempt at the same order or subscription.
+func WithIdempotencyKey(key string) option.RequestOption {
+	return option.WithHeader(idempotencyHeader, key)
+}
+
+// retryMiddleware retries requests that are safe to repeat, reads and those
+// with an idempotency key, on network errors and transient statuses, with
+// exponential backoff and full jitter. Anything else is sent exactly once.
+func retryMiddleware(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	if !isIdempotent(req) || (req.Body != nil && req.GetBody == nil) {
+		return next(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := next(req)
+		if attempt == maxAttempts || !isTransient(ctx, resp, err) {
+			return resp, err
+		}
+
+		wait := backoff(attempt, resp)
+		if err == nil {
+			err = errors.New(resp.Status)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logger.FromContext(ctx).Info(
+			"retrying api request",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"attempt", attempt,
+			"wait", wait,
+			"error", err,
+		)
+		if observe, ok := ctx.Value(retryObserverKey{}).(func(Retry)); ok {
+			observe(Retry{Attempt: attempt, MaxAttempts: maxAttempts, Wait: wait, Err: err})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(idempotencyHeader) != ""
+}
+
+func isTransient(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff is how long to wait before the next attempt: what the API asked
+// for in Retry-After if that's reasonable, otherwise a random time up to an
+// exponentially growing, capped, delay.
+func backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if wait := time.Duration(seconds) * time.Second; wait <= maxDelay {
+				return wait
+			}
+		}
+	}
+
+	ceiling := min(baseDelay<<(attempt-1), maxDelay)
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryMiddleware(t *testing.T) {
+	delay := baseDelay
+	baseDelay = time.Millisecond
+	t.Cleanup(func() { baseDelay = delay })
+	tests := []struct {
+		name     string
+		method   string
+		key      string
+		statuses []int
+		want     int
+		attempts int
+	}{
+		{"read recovers", http.MethodGet, "", []int{503, 502, 200}, 200, 3},
+		{"read gives up", http.MethodGet, "", []int{503, 503, 503, 503, 503}, 503, maxAttempts},
+		{"client error", http.MethodGet, "", []int{400, 200}, 400, 1},
+		{"create without key", http.MethodPost, "", []int{503, 200}, 503, 1},
+		{"create with key", http.MethodPost, "order-1", []int{503, 200}, 200, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := []byte(`{"quantity":1}`)
+			req, _ := http.NewRequest(test.method, "http://api.test/cart", bytes.NewReader(body))
+			if test.key != "" {
+				req.Header.Set(idempotencyHeader, test.key)
+			}
+
+			attempts := 0
+			resp, err := retryMiddleware(req, func(req *http.Request) (*http.Response, error) {
+				if sent, _ := io.ReadAll(req.Body); !bytes.Equal(sent, body) {
+					t.Errorf("attempt %d sent %q, want %q", attempts+1, sent, body)
+				}
+				status := test.statuses[attempts]
+				attempts++
+				return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.want || attempts != test.attempts {
+				t.Errorf("got %d after %d attempts, want %d after %d", resp.StatusCode, attempts, test.want, test.attempts)
+			}
+		})
+	}
+}
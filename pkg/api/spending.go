@@ -0,0 +1,122 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// SpendTotal is what was spent on one month, product or address, in cents
+// (USD).
+type SpendTotal struct {
+	Key    string
+	Amount int64
+}
+
+// Spending sums up a customer's orders.
+type Spending struct {
+	Total  int64
+	Orders int
+	// Months runs from the month of the first order to that of the last, as
+	// "2006-01", including months without orders.
+	Months []SpendTotal
+	// Products are keyed by product ID, every variant of a product counting
+	// towards it, the most spent on first. Variants of products no longer
+	// sold are keyed by their own ID.
+	Products []SpendTotal
+	// Addresses are keyed by street and city, the most spent on first.
+	Addresses []SpendTotal
+}
+
+// NewSpending sums up orders per month, product and address, finding the
+// product of each item's variant in products. Orders count with what was
+// charged to the card, i.e. with tax and shipping but without promo discounts
+// or what gift cards paid. Products only count with what their items cost.
+func NewSpending(orders []terminal.Order, products []terminal.Product) Spending {
+	spending := Spending{Orders: len(orders)}
+	productIDs := map[string]string{}
+	for _, product := range products {
+		for _, variant := range product.Variants {
+			productIDs[variant.ID] = product.ID
+		}
+	}
+
+	months := map[string]int64{}
+	productTotals := map[string]int64{}
+	addresses := map[string]int64{}
+
+	var first, last time.Time
+	for _, order := range orders {
+		total := OrderTotals(order).Due
+		spending.Total += total
+
+		for _, item := range order.Items {
+			productID, ok := productIDs[item.ProductVariantID]
+			if !ok {
+				productID = item.ProductVariantID
+			}
+			productTotals[productID] += item.Amount
+		}
+		addresses[order.Shipping.Street1+", "+order.Shipping.City] += total
+
+		created, err := time.Parse(time.RFC3339, order.Created)
+		if err != nil {
+			continue
+		}
+		month := time.Date(created.Year(), created.Month(), 1, 0, 0, 0, 0, time.UTC)
+		months[month.Format("2006-01")] += total
+		if first.IsZero() || month.Before(first) {
+			first = month
+		}
+		if month.After(last) {
+			last = month
+		}
+	}
+
+	if !first.IsZero() {
+		for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+			key := month.Format("2006-01")
+			spending.Months = append(spending.Months, SpendTotal{Key: key, Amount: months[key]})
+		}
+	}
+	spending.Products = sortTotals(productTotals)
+	spending.Addresses = sortTotals(addresses)
+	return spending
+}
+
+func sortTotals(amounts map[string]int64) []SpendTotal {
+	totals := []SpendTotal{}
+	for key, amount := range amounts {
+		totals = append(totals, SpendTotal{Key: key, Amount: amount})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Amount != totals[j].Amount {
+			return totals[i].Amount > totals[j].Amount
+		}
+		return totals[i].Key < totals[j].Key
+	})
+	return totals
+}
+
+// weeksPerMonth is how many weeks an average month has.
+const weeksPerMonth = 52.0 / 12.0
+
+// MonthlySubscriptionSpend projects what subscriptions cost in an average
+// month, with tax. prices are product variant prices by ID, in cents (USD).
+// Fixed schedules ship monthly.
+func MonthlySubscriptionSpend(subscriptions []terminal.Subscription, prices map[string]int64) int64 {
+	var monthly float64
+	for _, subscription := range subscriptions {
+		amount := prices[subscription.ProductVariantID] * subscription.Quantity
+		if tax := SubscriptionTax(subscription); tax != nil {
+			amount += tax.Amount
+		}
+		perMonth := 1.0
+		if subscription.Schedule.Type == terminal.SubscriptionScheduleTypeWeekly && subscription.Schedule.Interval > 0 {
+			perMonth = weeksPerMonth / float64(subscription.Schedule.Interval)
+		}
+		monthly += float64(amount) * perMonth
+	}
+	return int64(monthly + 0.5)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestNewSpending(t *testing.T) {
+	orders := []terminal.Order{
+		{
+			ID:       "ord_1",
+			Created:  "2026-07-03T10:00:00Z",
+			Amount:   terminal.OrderAmount{Subtotal: 2200, Shipping: 800},
+			Items:    []terminal.OrderItem{{Amount: 2200, Quantity: 1, ProductVariantID: "var_1"}},
+			Shipping: terminal.OrderShipping{Street1: "1 Main St", City: "Austin"},
+		},
+		{
+			ID:      "ord_2",
+			Created: "2026-09-20T10:00:00Z",
+			Amount:  terminal.OrderAmount{Subtotal: 4400},
+			Items: []terminal.OrderItem{
+				{Amount: 2200, Quantity: 1, ProductVariantID: "var_1"},
+				{Amount: 2200, Quantity: 1, ProductVariantID: "var_2"},
+			},
+			Shipping: terminal.OrderShipping{Street1: "2 Side St", City: "Austin"},
+		},
+		{
+			ID:       "ord_3",
+			Created:  "not a date",
+			Amount:   terminal.OrderAmount{Subtotal: 3000},
+			Items:    []terminal.OrderItem{{Amount: 3000, Quantity: 1, ProductVariantID: "var_gone"}},
+			Shipping: terminal.OrderShipping{Street1: "2 Side St", City: "Austin"},
+		},
+	}
+	products := []terminal.Product{{ID: "prd_1", Variants: []terminal.ProductVariant{{ID: "var_1"}, {ID: "var_2"}}}}
+
+	spending := NewSpending(orders, products)
+	if spending.Total != 10400 || spending.Orders != 3 {
+		t.Errorf("got total %d over %d orders", spending.Total, spending.Orders)
+	}
+	// orders without a date count towards the total but no month
+	months := []SpendTotal{{"2026-07", 3000}, {"2026-08", 0}, {"2026-09", 4400}}
+	if len(spending.Months) != len(months) {
+		t.Fatalf("got months %+v", spending.Months)
+	}
+	for i, month := range months {
+		if spending.Months[i] != month {
+			t.Errorf("got month %+v, want %+v", spending.Months[i], month)
+		}
+	}
+	wantProducts := []SpendTotal{{"prd_1", 6600}, {"var_gone", 3000}}
+	if len(spending.Products) != 2 || spending.Products[0] != wantProducts[0] || spending.Products[1] != wantProducts[1] {
+		t.Errorf("got products %+v, want %+v", spending.Products, wantProducts)
+	}
+	if spending.Addresses[0] != (SpendTotal{"2 Side St, Austin", 7400}) {
+		t.Errorf("got top address %+v", spending.Addresses[0])
+	}
+}
+
+func TestMonthlySubscriptionSpend(t *testing.T) {
+	subscriptions := []terminal.Subscription{
+		{ID: "sub_1", ProductVariantID: "var_cron", Quantity: 1, Schedule: terminal.SubscriptionSchedule{Type: terminal.SubscriptionScheduleTypeFixed}},
+		{ID: "sub_2", ProductVariantID: "var_1", Quantity: 2, Schedule: terminal.SubscriptionSchedule{Type: terminal.SubscriptionScheduleTypeWeekly, Interval: 4}},
+	}
+	prices := map[string]int64{"var_cron": 3000, "var_1": 2200}
+	// 3000 + 4400 * 13/12
+	if got := MonthlySubscriptionSpend(subscriptions, prices); got != 7767 {
+		t.Errorf("got %d", got)
+	}
+}
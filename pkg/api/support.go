@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Authors of a support ticket message.
+const (
+	AuthorCustomer = "customer"
+	AuthorShop     = "shop"
+)
+
+// Statuses of a support ticket.
+const (
+	TicketOpen   = "open"
+	TicketClosed = "closed"
+)
+
+// TicketMessage is one message in a support conversation.
+type TicketMessage struct {
+	ID      string `json:"id"`
+	Author  string `json:"author"`
+	Body    string `json:"body"`
+	Created string `json:"created"`
+}
+
+// Ticket is a conversation with the shop, optionally about an order.
+type Ticket struct {
+	ID      string `json:"id"`
+	Subject string `json:"subject"`
+	OrderID string `json:"orderID,omitempty"`
+	Status  string `json:"status"`
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+	// Messages are oldest first. Tickets are listed without them.
+	Messages []TicketMessage `json:"messages,omitempty"`
+}
+
+// TicketParams opens a ticket with its first message.
+type TicketParams struct {
+	Subject string `json:"subject"`
+	OrderID string `json:"orderID,omitempty"`
+	Message string `json:"message"`
+}
+
+type ticketReplyParams struct {
+	Body string `json:"body"`
+}
+
+type ticketListResponse struct {
+	Data []Ticket `json:"data"`
+}
+
+type ticketResponse struct {
+	Data Ticket `json:"data"`
+}
+
+// Closed is true once the shop has resolved a ticket, which can't be
+// replied to after.
+func (t Ticket) Closed() bool {
+	return t.Status == TicketClosed
+}
+
+// Awaiting is true while the customer spoke last, i.e. the shop owes a
+// reply.
+func (t Ticket) Awaiting() bool {
+	if t.Closed() || len(t.Messages) == 0 {
+		return false
+	}
+	return t.Messages[len(t.Messages)-1].Author == AuthorCustomer
+}
+
+// ListTickets returns the current user's support tickets, most recently
+// updated first.
+func ListTickets(ctx context.Context, client *terminal.Client) ([]Ticket, error) {
+	response := ticketListResponse{}
+	if err := client.Get(ctx, "support/ticket", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// GetTicket returns a ticket with all of its messages.
+func GetTicket(ctx context.Context, client *terminal.Client, id string) (Ticket, error) {
+	if id == "" {
+		return Ticket{}, fmt.Errorf("missing required id parameter")
+	}
+	response := ticketResponse{}
+	if err := client.Get(ctx, "support/ticket/"+url.PathEscape(id), nil, &response); err != nil {
+		return Ticket{}, err
+	}
+	return response.Data, nil
+}
+
+// NewTicket opens a support ticket.
+func NewTicket(ctx context.Context, client *terminal.Client, params TicketParams) (Ticket, error) {
+	response := ticketResponse{}
+	if err := client.Post(ctx, "support/ticket", params, &response); err != nil {
+		return Ticket{}, err
+	}
+	return response.Data, nil
+}
+
+// ReplyTicket adds the customer's message to a ticket and returns it with
+// all of its messages.
+func ReplyTicket(ctx context.Context, client *terminal.Client, id string, body string) (Ticket, error) {
+	if id == "" {
+		return Ticket{}, fmt.Errorf("missing required id parameter")
+	}
+	params := ticketReplyParams{Body: body}
+	response := ticketResponse{}
+	if err := client.Post(ctx, "support/ticket/"+url.PathEscape(id)+"/message", params, &response); err != nil {
+		return Ticket{}, err
+	}
+	return response.Data, nil
+}
@@ -0,0 +1,24 @@
+package api
+
+import "testing"
+
+func TestTicketAwaiting(t *testing.T) {
+	customer := TicketMessage{ID: "msg_1", Author: AuthorCustomer, Body: "where's my coffee?"}
+	shop := TicketMessage{ID: "msg_2", Author: AuthorShop, Body: "on its way"}
+
+	tests := []struct {
+		name   string
+		ticket Ticket
+		want   bool
+	}{
+		{name: "no messages", ticket: Ticket{Status: TicketOpen}},
+		{name: "shop replied", ticket: Ticket{Status: TicketOpen, Messages: []TicketMessage{customer, shop}}},
+		{name: "customer wrote last", ticket: Ticket{Status: TicketOpen, Messages: []TicketMessage{customer, shop, customer}}, want: true},
+		{name: "closed", ticket: Ticket{Status: TicketClosed, Messages: []TicketMessage{customer}}},
+	}
+	for _, test := range tests {
+		if got := test.ticket.Awaiting(); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// Kinds of tax the API charges, depending on where an order ships.
+const (
+	TaxSales = "sales"
+	TaxVAT   = "vat"
+)
+
+// Tax is the tax charged on an order, worked out by the API for its address.
+type Tax struct {
+	Kind string `json:"kind"`
+	// Rate is a fraction, e.g. 0.2 for 20% VAT.
+	Rate float64 `json:"rate"`
+	// Amount is in cents (USD).
+	Amount int64 `json:"amount"`
+	// VATID is the business buyer's VAT identification number, if they gave
+	// one.
+	VATID string `json:"vatID"`
+	// ReverseCharge is true when a valid VAT ID from another EU country means
+	// the buyer accounts for the VAT instead, so none is charged.
+	ReverseCharge bool `json:"reverseCharge"`
+}
+
+// Label names the tax for a line of totals, e.g. "vat 20%".
+func (t Tax) Label() string {
+	name := "tax"
+	if t.Kind == TaxVAT {
+		name = "vat"
+	}
+	if t.Rate == 0 {
+		return name
+	}
+	percent := math.Round(t.Rate*10000) / 100
+	return name + " " + strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
+
+type cartVATIDParams struct {
+	VATID string `json:"vatID"`
+}
+
+type taxEstimateResponse struct {
+	Data Tax `json:"data"`
+}
+
+// SetCartVATID puts a business buyer's VAT ID on the current user's cart,
+// which reprices its tax. An empty ID removes it.
+func SetCartVATID(ctx context.Context, client *terminal.Client, vatID string) error {
+	params := cartVATIDParams{VATID: vatID}
+	return client.Put(ctx, "cart/vat", params, nil)
+}
+
+// EstimateTax returns the tax on amount shipped to addressID, for orders that
+// aren't placed from the cart, e.g. subscriptions.
+func EstimateTax(ctx context.Context, client *terminal.Client, addressID string, amount int64) (*Tax, error) {
+	if addressID == "" {
+		return nil, fmt.Errorf("missing required addressID parameter")
+	}
+	query := url.Values{}
+	query.Set("addressID", addressID)
+	query.Set("amount", strconv.FormatInt(amount, 10))
+	response := taxEstimateResponse{}
+	if err := client.Get(ctx, "tax?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// CartTax returns the tax on the current user's cart, or nil before it has an
+// address.
+func CartTax(cart terminal.Cart) *Tax {
+	field, ok := cart.Amount.JSON.ExtraFields["tax"]
+	if tax, ok := parseExtra[Tax](field, ok); ok {
+		return &tax
+	}
+	return nil
+}
+
+// OrderTax returns the tax an order was charged, or nil if it had none.
+func OrderTax(order terminal.Order) *Tax {
+	field, ok := order.Amount.JSON.ExtraFields["tax"]
+	if tax, ok := parseExtra[Tax](field, ok); ok {
+		return &tax
+	}
+	return nil
+}
+
+// SubscriptionTax returns the tax charged on each of a subscription's
+// shipments, or nil if it has none.
+func SubscriptionTax(subscription terminal.Subscription) *Tax {
+	field, ok := subscription.JSON.ExtraFields["tax"]
+	if tax, ok := parseExtra[Tax](field, ok); ok {
+		return &tax
+	}
+	return nil
+}
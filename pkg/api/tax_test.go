@@ -0,0 +1,19 @@
+package api
+
+import "testing"
+
+func TestTaxLabel(t *testing.T) {
+	tests := []struct {
+		tax  Tax
+		want string
+	}{
+		{Tax{Kind: TaxSales, Rate: 0.0825}, "tax 8.25%"},
+		{Tax{Kind: TaxVAT, Rate: 0.19}, "vat 19%"},
+		{Tax{Kind: TaxVAT, ReverseCharge: true}, "vat"},
+	}
+	for _, test := range tests {
+		if got := test.tax.Label(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/terminaldotshop/terminal/go/pkg/resource"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds everything the binaries need to run. Each field can be set,
+// from lowest to highest precedence, by SST resources, the YAML config file,
+// its env var or its command line flag.
+type Config struct {
+	APIURL             string `yaml:"api_url" env:"TERMINAL_API_URL" flag:"api-url" usage:"terminal api url"`
+	AuthURL            string `yaml:"auth_url" env:"TERMINAL_AUTH_URL" flag:"auth-url" usage:"terminal auth url"`
+	AuthFingerprintKey string `yaml:"auth_fingerprint_key" env:"TERMINAL_AUTH_FINGERPRINT_KEY" flag:"auth-fingerprint-key" usage:"client secret used to exchange key fingerprints for tokens"`
+	StripePublicKey    string `yaml:"stripe_public_key" env:"TERMINAL_STRIPE_PUBLIC_KEY" flag:"stripe-public-key" usage:"stripe publishable key"`
+	IpinfoToken        string `yaml:"ipinfo_token" env:"TERMINAL_IPINFO_TOKEN" flag:"ipinfo-token" usage:"ipinfo.io token"`
+	SSHHostKey         string `yaml:"ssh_host_key" env:"TERMINAL_SSH_HOST_KEY" flag:"ssh-host-key" usage:"PEM encoded ssh host key"`
+	SSHHostKeyPath     string `yaml:"ssh_host_key_path" env:"TERMINAL_SSH_HOST_KEY_PATH" flag:"ssh-host-key-path" usage:"ssh host key file, created if missing and no PEM key is set"`
+	SSHPort            string `yaml:"ssh_port" env:"SSH_PORT" flag:"ssh-port" usage:"ssh listen port"`
+	HTTPPort           string `yaml:"http_port" env:"HTTP_PORT" flag:"http-port" usage:"http listen port"`
+	LogLevel           string `yaml:"log_level" env:"LOG_LEVEL" flag:"log-level" usage:"debug, info, warn or error"`
+	TraceExporter      string `yaml:"trace_exporter" env:"TRACE_EXPORTER" flag:"trace-exporter" usage:"otlp, stdout or empty to disable tracing"`
+	TrustedProxies     string `yaml:"trusted_proxies" env:"TERMINAL_TRUSTED_PROXIES" flag:"trusted-proxies" usage:"comma separated IPs or CIDRs allowed to send PROXY protocol headers on the ssh listener, empty disables it"`
+	RecordingDir       string `yaml:"recording_dir" env:"TERMINAL_RECORDING_DIR" flag:"recording-dir" usage:"directory to record tui sessions that opt in to as asciicast, empty disables recording"`
+	RecordingRetention string `yaml:"recording_retention" env:"TERMINAL_RECORDING_RETENTION" flag:"recording-retention" usage:"how long recordings are kept, e.g. 168h"`
+	StatusFile         string `yaml:"status_file" env:"TERMINAL_STATUS_FILE" flag:"status-file" usage:"YAML file with the maintenance status and banner, reloaded when it changes"`
+	AdminKeys          string `yaml:"admin_keys" env:"TERMINAL_ADMIN_KEYS" flag:"admin-keys" usage:"comma separated SHA256 fingerprints of the keys allowed to run admin commands"`
+	CardCollectTimeout string `yaml:"card_collect_timeout" env:"TERMINAL_CARD_COLLECT_TIMEOUT" flag:"card-collect-timeout" usage:"how long to wait for a card entered in the browser before giving up, e.g. 10m"`
+	OrderStatusURL     string `yaml:"order_status_url" env:"TERMINAL_ORDER_STATUS_URL" flag:"order-status-url" usage:"page where customers follow an order, with {id} for the order ID, empty leaves the link out"`
+	WebCookieSecret    string `yaml:"web_cookie_secret" env:"TERMINAL_WEB_COOKIE_SECRET" flag:"web-cookie-secret" usage:"signs browser identity cookies, setting it enables the browser terminal at /terminal/"`
+}
+
+// configEnv names the config file when -config isn't given.
+const configEnv = "TERMINAL_CONFIG"
+
+func defaults() Config {
+	return Config{
+		SSHHostKeyPath:     ".ssh/id_ed25519",
+		SSHPort:            "2222",
+		HTTPPort:           "8000",
+		LogLevel:           "info",
+		RecordingRetention: "168h",
+		CardCollectTimeout: "10m",
+	}
+}
+
+// Load builds the config for the binary called name from args (without the
+// program name) and the environment, then validates it.
+func Load(name string, args []string) (*Config, error) {
+	config := defaults()
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	path := flags.String("config", os.Getenv(configEnv), "path to a YAML config file (env "+configEnv+")")
+	values := map[string]*string{}
+	eachField(&config, func(field reflect.StructField, _ reflect.Value) {
+		name := field.Tag.Get("flag")
+		usage := fmt.Sprintf("%s (env %s)", field.Tag.Get("usage"), field.Tag.Get("env"))
+		values[name] = flags.String(name, "", usage)
+	})
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	resources, err := resource.Load()
+	if err != nil {
+		return nil, err
+	}
+	config.fromResources(resources)
+
+	if *path != "" {
+		data, err := os.ReadFile(*path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parsing config file %s: %w", *path, err)
+		}
+	}
+
+	eachField(&config, func(field reflect.StructField, value reflect.Value) {
+		if env, ok := os.LookupEnv(field.Tag.Get("env")); ok {
+			value.SetString(env)
+		}
+	})
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	eachField(&config, func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("flag")
+		if set[name] {
+			value.SetString(*values[name])
+		}
+	})
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Config) fromResources(resources resource.Resources) {
+	sst := Config{
+		APIURL:             resources.Api.Url,
+		AuthURL:            resources.Auth.Url,
+		AuthFingerprintKey: resources.AuthFingerprintKey.Value,
+		StripePublicKey:    resources.StripePublic.Value,
+		IpinfoToken:        resources.IpinfoToken.Value,
+		SSHHostKey:         resources.SSHKey.Private,
+	}
+	source := reflect.ValueOf(sst)
+	eachField(c, func(field reflect.StructField, value reflect.Value) {
+		if v := source.FieldByName(field.Name).String(); v != "" {
+			value.SetString(v)
+		}
+	})
+}
+
+// Validate reports every missing or malformed setting at once, naming where
+// each one can be set.
+func (c *Config) Validate() error {
+	errs := []error{}
+
+	required := map[string]string{
+		"APIURL":             c.APIURL,
+		"AuthURL":            c.AuthURL,
+		"AuthFingerprintKey": c.AuthFingerprintKey,
+		"StripePublicKey":    c.StripePublicKey,
+	}
+	eachField(c, func(field reflect.StructField, _ reflect.Value) {
+		value, ok := required[field.Name]
+		if ok && value == "" {
+			errs = append(errs, fmt.Errorf(
+				"%s is required: set %s in the config file, env %s or flag -%s",
+				field.Tag.Get("yaml"),
+				field.Tag.Get("yaml"),
+				field.Tag.Get("env"),
+				field.Tag.Get("flag"),
+			))
+		}
+	})
+
+	ports := []struct{ name, value string }{
+		{"ssh_port", c.SSHPort},
+		{"http_port", c.HTTPPort},
+	}
+	for _, port := range ports {
+		if n, err := strconv.Atoi(port.value); err != nil || n < 1 || n > 65535 {
+			errs = append(errs, fmt.Errorf("%s must be a port number, got %q", port.name, port.value))
+		}
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(c.LogLevel))); err != nil {
+		errs = append(errs, fmt.Errorf("log_level must be debug, info, warn or error, got %q", c.LogLevel))
+	}
+
+	switch c.TraceExporter {
+	case "", "otlp", "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("trace_exporter must be otlp, stdout or empty, got %q", c.TraceExporter))
+	}
+
+	if retention, err := time.ParseDuration(c.RecordingRetention); err != nil || retention <= 0 {
+		errs = append(errs, fmt.Errorf("recording_retention must be a positive duration such as 168h, got %q", c.RecordingRetention))
+	}
+
+	if timeout, err := time.ParseDuration(c.CardCollectTimeout); err != nil || timeout <= 0 {
+		errs = append(errs, fmt.Errorf("card_collect_timeout must be a positive duration such as 10m, got %q", c.CardCollectTimeout))
+	}
+
+	for _, proxy := range c.TrustedProxyList() {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("trusted_proxies must be IPs or CIDRs, got %q", proxy))
+		}
+	}
+
+	for _, key := range c.AdminKeyList() {
+		if !strings.HasPrefix(key, "SHA256:") {
+			errs = append(errs, fmt.Errorf("admin_keys must be SHA256 fingerprints as printed by ssh-keygen -lf, got %q", key))
+		}
+	}
+
+	if c.OrderStatusURL != "" {
+		u, err := url.Parse(c.OrderStatusURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || !strings.Contains(c.OrderStatusURL, "{id}") {
+			errs = append(errs, fmt.Errorf("order_status_url must be an http(s) URL with {id} for the order ID, got %q", c.OrderStatusURL))
+		}
+	}
+
+	if c.WebCookieSecret != "" && len(c.WebCookieSecret) < 32 {
+		errs = append(errs, errors.New("web_cookie_secret must be at least 32 characters"))
+	}
+
+	if c.SSHHostKey == "" && c.SSHHostKeyPath == "" {
+		errs = append(errs, errors.New("one of ssh_host_key or ssh_host_key_path is required"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// TrustedProxyList splits TrustedProxies into its IPs and CIDRs.
+func (c *Config) TrustedProxyList() []string {
+	return splitList(c.TrustedProxies)
+}
+
+// AdminKeyList splits AdminKeys into its fingerprints.
+func (c *Config) AdminKeyList() []string {
+	return splitList(c.AdminKeys)
+}
+
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// RecordingMaxAge is RecordingRetention parsed, valid once Validate passes.
+func (c *Config) RecordingMaxAge() time.Duration {
+	retention, _ := time.ParseDuration(c.RecordingRetention)
+	return retention
+}
+
+// CardCollectMaxWait is CardCollectTimeout parsed, valid once Validate passes.
+func (c *Config) CardCollectMaxWait() time.Duration {
+	timeout, _ := time.ParseDuration(c.CardCollectTimeout)
+	return timeout
+}
+
+func eachField(c *Config, fn func(field reflect.StructField, value reflect.Value)) {
+	val := reflect.ValueOf(c).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		fn(val.Type().Field(i), val.Field(i))
+	}
+}
@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terminaldotshop/terminal/go/pkg/config"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	t.Setenv("SST_RESOURCE_Api", `{"url":"https://sst.example"}`)
+	t.Setenv("SST_RESOURCE_Auth", `{"url":"https://auth.sst.example"}`)
+	t.Setenv("SST_RESOURCE_StripePublic", `{"value":"pk_sst"}`)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	file := "auth_url: https://auth.file.example\nauth_fingerprint_key: file-secret\nssh_port: \"2022\"\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TERMINAL_AUTH_FINGERPRINT_KEY", "env-secret")
+	t.Setenv("SSH_PORT", "2023")
+
+	cfg, err := config.Load("test", []string{"-config", path, "-ssh-port", "2024"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][2]string{
+		"api_url":              {cfg.APIURL, "https://sst.example"},
+		"auth_url":             {cfg.AuthURL, "https://auth.file.example"},
+		"auth_fingerprint_key": {cfg.AuthFingerprintKey, "env-secret"},
+		"stripe_public_key":    {cfg.StripePublicKey, "pk_sst"},
+		"ssh_port":             {cfg.SSHPort, "2024"},
+		"http_port":            {cfg.HTTPPort, "8000"},
+	}
+	for name, values := range expected {
+		if values[0] != values[1] {
+			t.Errorf("%s: expected %q, got %q", name, values[1], values[0])
+		}
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	_, err := config.Load("test", []string{"-http-port", "eighty", "-log-level", "loud", "-trusted-proxies", "10.0.0.0/8, lb", "-order-status-url", "terminal.shop/orders"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, want := range []string{
+		"api_url is required",
+		"env TERMINAL_AUTH_URL",
+		"flag -stripe-public-key",
+		"http_port must be a port number",
+		"log_level must be",
+		`trusted_proxies must be IPs or CIDRs, got "lb"`,
+		"order_status_url must be an http(s) URL",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got:\n%s", want, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+#!/bin/sh
+# Downloads the pinned xterm.js release the page is served with into this
+# directory. npm checks each package against the registry's integrity hash.
+# Run with `go generate ./pkg/gateway` and commit the result.
+set -eu
+
+XTERM=@xterm/xterm@5.5.0
+FIT=@xterm/addon-fit@0.10.0
+
+cd "$(dirname "$0")"
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+npm pack --silent --pack-destination "$tmp" "$XTERM" "$FIT" >/dev/null
+tar -xzf "$tmp"/xterm-xterm-*.tgz -C "$tmp" package/lib/xterm.js package/css/xterm.css
+cp "$tmp/package/lib/xterm.js" "$tmp/package/css/xterm.css" .
+rm -rf "$tmp/package"
+tar -xzf "$tmp"/xterm-addon-fit-*.tgz -C "$tmp" package/lib/addon-fit.js
+cp "$tmp/package/lib/addon-fit.js" .
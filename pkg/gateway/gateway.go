@@ -0,0 +1,256 @@
+// Package gateway serves the shop TUI to browsers: a small xterm.js page
+// talks over a WebSocket to a Bubble Tea program running the same model as
+// the SSH server.
+package gateway
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/muesli/termenv"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/recording"
+	"github.com/terminaldotshop/terminal/go/pkg/sessions"
+	"github.com/terminaldotshop/terminal/go/pkg/telemetry"
+	"github.com/terminaldotshop/terminal/go/pkg/tui"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//go:generate sh assets/fetch.sh
+
+//go:embed index.html
+var index []byte
+
+// assets are the page's scripts and styles, vendored by assets/fetch.sh so
+// no third party serves code to the page.
+//
+//go:embed assets
+var assets embed.FS
+
+// assetFiles are the files the page loads from assets.
+var assetFiles = []string{"xterm.js", "xterm.css", "addon-fit.js"}
+
+// fingerprintPrefix keeps browser identities apart from SSH key
+// fingerprints.
+const fingerprintPrefix = "web:"
+
+// message is sent by the page for every keystroke and terminal resize.
+type message struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+	Cols int    `json:"cols"`
+	Rows int    `json:"rows"`
+}
+
+type Gateway struct {
+	identity     identity
+	recordingDir string
+	registry     *sessions.Registry
+	files        fs.FS
+}
+
+// New returns a gateway that signs identity cookies with secret, tracks its
+// sessions in registry and, when recordingDir isn't empty, records the
+// sessions that opt in to it. It fails when the page's assets weren't
+// embedded, as the page can't start without them.
+func New(secret, recordingDir string, registry *sessions.Registry) (*Gateway, error) {
+	files, err := fs.Sub(assets, "assets")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAssets(files); err != nil {
+		return nil, err
+	}
+	return &Gateway{
+		identity:     identity{secret: []byte(secret)},
+		recordingDir: recordingDir,
+		registry:     registry,
+		files:        files,
+	}, nil
+}
+
+// checkAssets reports every file in assetFiles that files is missing.
+func checkAssets(files fs.FS) error {
+	errs := []error{}
+	for _, name := range assetFiles {
+		if _, err := fs.Stat(files, name); err != nil {
+			errs = append(errs, fmt.Errorf("browser terminal asset %s is missing, run go generate ./pkg/gateway: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler serves the page at prefix + "/" and its WebSocket at prefix +
+// "/ws".
+func (g *Gateway) Handler(prefix string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix+"/", g.serveIndex)
+	mux.HandleFunc(prefix+"/ws", g.serveSession)
+	mux.Handle(prefix+"/assets/", http.StripPrefix(prefix+"/assets/", g.serveAssets(g.files)))
+	return mux
+}
+
+// serveAssets serves the vendored files the page loads and nothing else
+// from the assets directory.
+func (g *Gateway) serveAssets(files fs.FS) http.Handler {
+	server := http.FileServerFS(files)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range assetFiles {
+			if r.URL.Path == name {
+				w.Header().Set("Cache-Control", "public, max-age=86400")
+				server.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+}
+
+func (g *Gateway) serveIndex(w http.ResponseWriter, r *http.Request) {
+	g.identity.ensure(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(index)
+}
+
+func (g *Gateway) serveSession(w http.ResponseWriter, r *http.Request) {
+	id, ok := g.identity.fromRequest(r)
+	if !ok {
+		http.Error(w, "missing identity, reload the page", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		logger.FromContext(r.Context()).Warn("websocket accept failed", "error", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	start := time.Now()
+	sessionID := uuid.NewString()
+	connCtx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	ctx := logger.WithSession(connCtx, sessionID)
+	ctx, span := telemetry.Start(
+		ctx,
+		"web.session",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("session.id", sessionID)),
+	)
+	defer span.End()
+
+	host, _, _ := net.SplitHostPort(r.RemoteAddr)
+	log := logger.FromContext(ctx)
+	log.Info("connect", "remote_addr", r.RemoteAddr, "user_agent", r.UserAgent(), "web", true)
+	defer func() { log.Info("disconnect", "duration", time.Since(start)) }()
+
+	session := sessions.New(sessionID, fingerprintPrefix+id, host, func() {
+		conn.Close(websocket.StatusPolicyViolation, "disconnected by an operator")
+		cancel()
+	})
+	session.Web = true
+	g.registry.Add(session)
+	defer g.registry.Remove(sessionID)
+	ctx = sessions.WithSession(ctx, session)
+
+	var recorder *recording.Recorder
+	// the page passes on ?record=1 to opt in
+	if g.recordingDir != "" && recording.Requested(r.URL.Query().Get("record")) {
+		recorder, err = recording.New(g.recordingDir, sessionID, 80, 24, "xterm-256color")
+		if err != nil {
+			log.Error("could not start recording", "error", err)
+		}
+		defer recorder.Close()
+		ctx = recording.WithRecorder(ctx, recorder)
+	}
+
+	output := &socketWriter{ctx: connCtx, conn: conn, recorder: recorder}
+	renderer := lipgloss.NewRenderer(output)
+	renderer.SetColorProfile(termenv.TrueColor)
+	renderer.SetHasDarkBackground(true)
+
+	// the page sends the browser's timezone
+	ctx = tui.WithTimezone(ctx, r.URL.Query().Get("tz"))
+	model, err := tui.NewModel(ctx, renderer, fingerprintPrefix+id, "", false, &host, []string{})
+	if err != nil {
+		log.Error("could not create model", "error", err)
+		conn.Close(websocket.StatusInternalError, "could not start session")
+		return
+	}
+
+	input, inputWriter := io.Pipe()
+	program := tea.NewProgram(
+		model,
+		tea.WithContext(connCtx),
+		tea.WithInput(input),
+		tea.WithOutput(output),
+		tea.WithAltScreen(),
+	)
+
+	session.Attach(connCtx, func(msg any) { program.Send(msg) })
+	session.Send(g.registry.Status())
+
+	go func() {
+		defer program.Quit()
+		defer inputWriter.Close()
+		defer cancel()
+		for {
+			_, data, err := conn.Read(connCtx)
+			if err != nil {
+				return
+			}
+			var msg message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				log.Warn("bad websocket message", "error", err)
+				continue
+			}
+			switch msg.Type {
+			case "input":
+				recorder.Input([]byte(msg.Data))
+				if _, err := inputWriter.Write([]byte(msg.Data)); err != nil {
+					return
+				}
+			case "resize":
+				if msg.Cols > 0 && msg.Rows > 0 {
+					recorder.Resize(msg.Cols, msg.Rows)
+					program.Send(tea.WindowSizeMsg{Width: msg.Cols, Height: msg.Rows})
+				}
+			}
+		}
+	}()
+
+	if _, err := program.Run(); err != nil && connCtx.Err() == nil {
+		log.Error("program exited", "error", err)
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// socketWriter sends program output to the browser as binary frames, which
+// xterm.js writes as-is.
+type socketWriter struct {
+	ctx      context.Context
+	conn     *websocket.Conn
+	recorder *recording.Recorder
+}
+
+func (s *socketWriter) Write(p []byte) (int, error) {
+	s.recorder.Output(p)
+	if err := s.conn.Write(s.ctx, websocket.MessageBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
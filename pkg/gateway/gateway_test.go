@@ -0,0 +1,26 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheckAssets(t *testing.T) {
+	files := fstest.MapFS{
+		"xterm.js":  {Data: []byte("")},
+		"xterm.css": {Data: []byte("")},
+	}
+	err := checkAssets(files)
+	if err == nil || !strings.Contains(err.Error(), "addon-fit.js") {
+		t.Fatalf("got %v, want addon-fit.js reported missing", err)
+	}
+	if strings.Contains(err.Error(), "xterm.js") {
+		t.Errorf("reported an embedded asset missing: %v", err)
+	}
+
+	files["addon-fit.js"] = &fstest.MapFile{Data: []byte("")}
+	if err := checkAssets(files); err != nil {
+		t.Errorf("got %v with every asset embedded", err)
+	}
+}
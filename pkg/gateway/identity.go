@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	cookieName   = "terminal_identity"
+	cookieMaxAge = 365 * 24 * time.Hour
+)
+
+// identity is a browser's stand-in for an SSH key: a random ID kept in a
+// cookie, signed so it can't be forged to act as someone else.
+type identity struct {
+	secret []byte
+}
+
+func (i identity) sign(id string) string {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(id))
+	return id + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns the ID in a cookie value, or false if it wasn't signed by
+// us.
+func (i identity) verify(value string) (string, bool) {
+	id, _, ok := strings.Cut(value, ".")
+	if !ok || id == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(i.sign(id)), []byte(value)) {
+		return "", false
+	}
+	return id, true
+}
+
+// fromRequest returns the ID in the request's identity cookie, if it has a
+// valid one.
+func (i identity) fromRequest(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(cookieName)
+	if err != nil {
+		return "", false
+	}
+	return i.verify(cookie.Value)
+}
+
+// ensure returns the request's ID, issuing a new cookie first if it has none.
+func (i identity) ensure(w http.ResponseWriter, r *http.Request) string {
+	if id, ok := i.fromRequest(r); ok {
+		return id
+	}
+
+	id := uuid.NewString()
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    i.sign(id),
+		Path:     "/",
+		MaxAge:   int(cookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteStrictMode,
+	})
+	return id
+}
@@ -0,0 +1,26 @@
+package gateway
+
+import "testing"
+
+func TestIdentityVerify(t *testing.T) {
+	i := identity{secret: []byte("0123456789abcdef0123456789abcdef")}
+	other := identity{secret: []byte("fedcba9876543210fedcba9876543210")}
+	signed := i.sign("abc")
+
+	if id, ok := i.verify(signed); !ok || id != "abc" {
+		t.Fatalf("verify(%q) = %q, %v; want abc, true", signed, id, ok)
+	}
+
+	forged := []string{
+		"",
+		"abc",
+		"abc.",
+		"xyz" + signed[3:],
+		other.sign("abc"),
+	}
+	for _, value := range forged {
+		if _, ok := i.verify(value); ok {
+			t.Errorf("verify(%q) accepted a forged cookie", value)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type contextKey struct{}
+
+var sessionKey = contextKey{}
+
+// Setup makes a JSON handler writing to w the default slog logger, keeping
+// the latest errors for RecentErrors. Level is one of debug, info, warn or
+// error and defaults to info when empty.
+func Setup(w io.Writer, level string) error {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(strings.ToLower(level))); err != nil {
+			return fmt.Errorf("invalid log level %q", level)
+		}
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl})
+	slog.SetDefault(slog.New(recentHandler{Handler: handler}))
+	return nil
+}
+
+// WithSession returns a copy of ctx carrying the session ID.
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionKey, sessionID)
+}
+
+// SessionID returns the session ID carried by ctx, or "" if there is none.
+func SessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionKey).(string)
+	return sessionID
+}
+
+// FromContext returns the default logger annotated with the session ID
+// carried by ctx, so every line for a session can be correlated.
+func FromContext(ctx context.Context) *slog.Logger {
+	sessionID := SessionID(ctx)
+	if sessionID == "" {
+		return slog.Default()
+	}
+	return slog.Default().With("session_id", sessionID)
+}
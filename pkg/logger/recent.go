@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// recentSize is how many error lines RecentErrors remembers.
+const recentSize = 100
+
+// Entry is an error logged by this process.
+type Entry struct {
+	Time    time.Time
+	Message string
+	Attrs   []slog.Attr
+}
+
+var recent = struct {
+	sync.Mutex
+	entries []Entry
+	next    int
+}{entries: make([]Entry, 0, recentSize)}
+
+// RecentErrors returns the last errors logged through the default logger,
+// oldest first, so operators can see them without reading the log stream.
+func RecentErrors() []Entry {
+	recent.Lock()
+	defer recent.Unlock()
+	entries := make([]Entry, 0, len(recent.entries))
+	entries = append(entries, recent.entries[recent.next:]...)
+	entries = append(entries, recent.entries[:recent.next]...)
+	return entries
+}
+
+func remember(entry Entry) {
+	recent.Lock()
+	defer recent.Unlock()
+	if len(recent.entries) < recentSize {
+		recent.entries = append(recent.entries, entry)
+		return
+	}
+	recent.entries[recent.next] = entry
+	recent.next = (recent.next + 1) % recentSize
+}
+
+// recentHandler remembers error records before passing them on.
+type recentHandler struct {
+	slog.Handler
+	attrs []slog.Attr
+}
+
+func (h recentHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		attrs := append([]slog.Attr{}, h.attrs...)
+		r.Attrs(func(attr slog.Attr) bool {
+			attrs = append(attrs, attr)
+			return true
+		})
+		remember(Entry{Time: r.Time, Message: r.Message, Attrs: attrs})
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h recentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return recentHandler{
+		Handler: h.Handler.WithAttrs(attrs),
+		attrs:   append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+func (h recentHandler) WithGroup(name string) slog.Handler {
+	return recentHandler{Handler: h.Handler.WithGroup(name), attrs: h.attrs}
+}
@@ -0,0 +1,260 @@
+// Package receipt renders an order as a receipt the customer can keep, in
+// plain text, Markdown or HTML.
+package receipt
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat reads a format by name or file extension, e.g. "md".
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "text", "txt":
+		return FormatText, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("unknown receipt format %q, use text, markdown or html", name)
+}
+
+const seller = "Terminal Products, Inc."
+
+// Line is one item on a receipt, its amount already formatted.
+type Line struct {
+	Description string
+	Quantity    int64
+	Amount      string
+}
+
+// Amount is a labelled sum of a receipt's totals, already formatted.
+type Amount struct {
+	Label string
+	Value string
+}
+
+// Receipt is an order laid out for rendering.
+type Receipt struct {
+	OrderID string
+	Number  int64
+	Date    string
+	Items   []Line
+	Totals  []Amount
+	Total   string
+	// GiftCard is what gift cards paid of the total and Due what was left to
+	// charge to the card, both empty when no gift card was used.
+	GiftCard string
+	Due      string
+	// Address is the shipping address, a line each.
+	Address []string
+	// Payment describes the card, empty when gift cards paid for it all.
+	Payment string
+	VATID   string
+	// ReverseCharge notes that the buyer accounts for the VAT.
+	ReverseCharge bool
+}
+
+// New lays out order as a receipt, naming items after products.
+func New(order terminal.Order, products []terminal.Product) Receipt {
+	r := Receipt{
+		OrderID: order.ID,
+		Number:  order.Index,
+		Date:    order.Created,
+	}
+
+	for _, item := range order.Items {
+		r.Items = append(r.Items, Line{
+			Description: describe(item, products),
+			Quantity:    item.Quantity,
+			Amount:      formatUSD(item.Amount),
+		})
+	}
+
+	totals := api.OrderTotals(order)
+	r.Totals = []Amount{
+		{Label: "subtotal", Value: formatUSD(totals.Subtotal)},
+		{Label: "shipping", Value: formatUSD(totals.Shipping)},
+	}
+	if totals.Discount > 0 {
+		r.Totals = append(r.Totals, Amount{Label: "discount", Value: "-" + formatUSD(totals.Discount)})
+	}
+	if tax := api.OrderTax(order); tax != nil {
+		r.Totals = append(r.Totals, Amount{Label: tax.Label(), Value: formatUSD(tax.Amount)})
+		r.VATID = tax.VATID
+		r.ReverseCharge = tax.ReverseCharge
+	}
+	r.Total = formatUSD(totals.Total)
+	if totals.GiftCard > 0 {
+		r.GiftCard = "-" + formatUSD(totals.GiftCard)
+		r.Due = formatUSD(totals.Due)
+	}
+
+	address := order.Shipping
+	r.Address = []string{address.Name, address.Street1}
+	if address.Street2 != "" {
+		r.Address = append(r.Address, address.Street2)
+	}
+	r.Address = append(r.Address, strings.Join([]string{address.City, address.Province, address.Country + " " + address.Zip}, ", "))
+
+	if card := api.OrderPayment(order); card != nil {
+		brand := card.Brand
+		if brand == "" {
+			brand = "card"
+		}
+		r.Payment = brand + " ending in " + card.Last4
+	}
+	return r
+}
+
+func describe(item terminal.OrderItem, products []terminal.Product) string {
+	for _, product := range products {
+		for _, variant := range product.Variants {
+			if variant.ID == item.ProductVariantID {
+				return product.Name + " (" + strings.ToLower(variant.Name) + ")"
+			}
+		}
+	}
+	if item.Description != "" {
+		return item.Description
+	}
+	return "unknown product"
+}
+
+func formatUSD(cents int64) string {
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
+
+// Render writes the receipt to w in format.
+func (r Receipt) Render(w io.Writer, format Format) error {
+	switch format {
+	case FormatText:
+		_, err := io.WriteString(w, r.text())
+		return err
+	case FormatMarkdown:
+		_, err := io.WriteString(w, r.markdown())
+		return err
+	case FormatHTML:
+		return htmlTemplate.Execute(w, r)
+	}
+	return fmt.Errorf("unknown receipt format %q", format)
+}
+
+// String is the plain text receipt.
+func (r Receipt) String() string {
+	return r.text()
+}
+
+func (r Receipt) text() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%s\nreceipt for order #%d\n\n", seller, r.Number)
+	fmt.Fprintf(&b, "order: %s\ndate:  %s\n", r.OrderID, r.Date)
+	if r.VATID != "" {
+		fmt.Fprintf(&b, "vat id: %s\n", r.VATID)
+	}
+	b.WriteString("\n")
+	for _, item := range r.Items {
+		fmt.Fprintf(&b, "%3dx %-36s %10s\n", item.Quantity, item.Description, item.Amount)
+	}
+	b.WriteString("\n")
+	for _, amount := range r.Totals {
+		fmt.Fprintf(&b, "%-41s %10s\n", amount.Label, amount.Value)
+	}
+	fmt.Fprintf(&b, "%-41s %10s\n", "total", r.Total)
+	if r.GiftCard != "" {
+		fmt.Fprintf(&b, "%-41s %10s\n", "gift card", r.GiftCard)
+		fmt.Fprintf(&b, "%-41s %10s\n", "due", r.Due)
+	}
+	if r.ReverseCharge {
+		b.WriteString("reverse charge: vat to be accounted for by the buyer\n")
+	}
+	b.WriteString("\nshipped to\n")
+	for _, line := range r.Address {
+		b.WriteString(line + "\n")
+	}
+	if r.Payment != "" {
+		b.WriteString("\npaid with " + r.Payment + "\n")
+	}
+	return b.String()
+}
+
+func (r Receipt) markdown() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "# Receipt for order #%d\n\n", r.Number)
+	fmt.Fprintf(&b, "%s\n\n", seller)
+	fmt.Fprintf(&b, "- order: `%s`\n- date: %s\n", r.OrderID, r.Date)
+	if r.VATID != "" {
+		fmt.Fprintf(&b, "- vat id: %s\n", r.VATID)
+	}
+	b.WriteString("\n| item | qty | amount |\n| --- | ---: | ---: |\n")
+	for _, item := range r.Items {
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", escapeMarkdown(item.Description), item.Quantity, item.Amount)
+	}
+	for _, amount := range r.Totals {
+		fmt.Fprintf(&b, "| %s | | %s |\n", amount.Label, amount.Value)
+	}
+	fmt.Fprintf(&b, "| **total** | | **%s** |\n", r.Total)
+	if r.GiftCard != "" {
+		fmt.Fprintf(&b, "| gift card | | %s |\n| due | | %s |\n", r.GiftCard, r.Due)
+	}
+	if r.ReverseCharge {
+		b.WriteString("\nReverse charge: VAT to be accounted for by the buyer.\n")
+	}
+	b.WriteString("\n## Shipped to\n\n")
+	b.WriteString(strings.Join(r.Address, "  \n") + "\n")
+	if r.Payment != "" {
+		b.WriteString("\n## Payment\n\nPaid with " + r.Payment + ".\n")
+	}
+	return b.String()
+}
+
+func escapeMarkdown(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt for order #{{.Number}}</title>
+<style>
+body { font-family: monospace; max-width: 40em; margin: 2em auto; }
+table { width: 100%; border-collapse: collapse; }
+td, th { padding: 0.25em 0; text-align: left; }
+.amount { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Receipt for order #{{.Number}}</h1>
+<p>` + seller + `</p>
+<p>order: {{.OrderID}}<br>date: {{.Date}}{{if .VATID}}<br>vat id: {{.VATID}}{{end}}</p>
+<table>
+<tr><th>item</th><th class="amount">qty</th><th class="amount">amount</th></tr>
+{{range .Items}}<tr><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{.Amount}}</td></tr>
+{{end}}{{range .Totals}}<tr><td>{{.Label}}</td><td></td><td class="amount">{{.Value}}</td></tr>
+{{end}}<tr><th>total</th><td></td><th class="amount">{{.Total}}</th></tr>
+{{if .GiftCard}}<tr><td>gift card</td><td></td><td class="amount">{{.GiftCard}}</td></tr>
+<tr><td>due</td><td></td><td class="amount">{{.Due}}</td></tr>
+{{end}}</table>
+{{if .ReverseCharge}}<p>Reverse charge: VAT to be accounted for by the buyer.</p>
+{{end}}<h2>Shipped to</h2>
+<p>{{range $i, $line := .Address}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+{{if .Payment}}<h2>Payment</h2>
+<p>Paid with {{.Payment}}.</p>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,90 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+const order = `{
+	"id": "ord_1",
+	"index": 3,
+	"created": "2026-10-01T12:00:00Z",
+	"amount": {"subtotal": 4400, "shipping": 800, "tax": {"kind": "vat", "rate": 0.2, "amount": 1040, "vatID": "DE123456789"}},
+	"items": [{"id": "itm_1", "amount": 4400, "quantity": 2, "productVariantID": "var_1"}],
+	"shipping": {"name": "Ada <Lovelace>", "street1": "1 Analytical Way", "city": "Berlin", "country": "DE", "zip": "10115"},
+	"card": {"brand": "visa", "last4": "4242"}
+}`
+
+func TestRender(t *testing.T) {
+	o := terminal.Order{}
+	if err := json.Unmarshal([]byte(order), &o); err != nil {
+		t.Fatal(err)
+	}
+	products := []terminal.Product{{Name: "segfault", Variants: []terminal.ProductVariant{{ID: "var_1", Name: "12oz"}}}}
+	r := New(o, products)
+
+	if r.Total != "$62.40" {
+		t.Errorf("got total %s", r.Total)
+	}
+
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{FormatText, []string{"order #3", "2x segfault (12oz)", "vat 20%", "$62.40", "vat id: DE123456789", "visa ending in 4242"}},
+		{FormatMarkdown, []string{"# Receipt for order #3", "| segfault (12oz) | 2 | $44.00 |", "**$62.40**"}},
+		{FormatHTML, []string{"<h1>Receipt for order #3</h1>", "Ada &lt;Lovelace&gt;", "visa ending in 4242"}},
+	}
+	for _, test := range tests {
+		b := bytes.Buffer{}
+		if err := r.Render(&b, test.format); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("%s receipt is missing %q:\n%s", test.format, want, b.String())
+			}
+		}
+	}
+}
+
+func TestCodes(t *testing.T) {
+	o := terminal.Order{}
+	body := `{"id": "ord_2", "amount": {"subtotal": 4400, "shipping": 800, "discount": 1000, "giftCard": 5000}}`
+	if err := json.Unmarshal([]byte(body), &o); err != nil {
+		t.Fatal(err)
+	}
+	r := New(o, nil)
+	if r.Total != "$42.00" || r.GiftCard != "-$42.00" || r.Due != "$0.00" {
+		t.Errorf("got total %s, gift card %s, due %s", r.Total, r.GiftCard, r.Due)
+	}
+	if r.Payment != "" {
+		t.Errorf("got payment %q for an order gift cards paid", r.Payment)
+	}
+
+	b := bytes.Buffer{}
+	if err := r.Render(&b, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"discount", "-$10.00", "gift card", "due"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("receipt is missing %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"md": FormatMarkdown, "TXT": FormatText, ".html": FormatHTML} {
+		got, err := ParseFormat(name)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected an error for pdf")
+	}
+}
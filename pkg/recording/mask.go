@@ -0,0 +1,83 @@
+package recording
+
+import "unicode/utf8"
+
+type escapeState int
+
+const (
+	ground escapeState = iota
+	escape
+	csi
+	str
+)
+
+// masker replaces digits in a terminal stream with '*', leaving escape
+// sequences, which are full of digits, untouched. It keeps its state across
+// writes since a sequence can be split between two of them.
+type masker struct {
+	state escapeState
+}
+
+func (m *masker) mask(p []byte, enabled bool) []byte {
+	out := make([]byte, len(p))
+	for i, b := range p {
+		out[i] = b
+		switch m.state {
+		case ground:
+			switch {
+			case b == 0x1b:
+				m.state = escape
+			case enabled && b >= '0' && b <= '9':
+				out[i] = '*'
+			}
+		case escape:
+			switch {
+			case b == '[':
+				m.state = csi
+			case b == ']' || b == 'P' || b == 'X' || b == '^' || b == '_':
+				m.state = str
+			case b >= 0x20 && b <= 0x2f:
+				// intermediate bytes, the sequence goes on
+			default:
+				m.state = ground
+			}
+		case csi:
+			if b >= 0x40 && b <= 0x7e {
+				m.state = ground
+			}
+		case str:
+			switch b {
+			case 0x07:
+				m.state = ground
+			case 0x1b:
+				m.state = escape
+			}
+		}
+	}
+	return out
+}
+
+// stream turns a byte stream into the strings asciicast events hold, masking
+// it when asked and holding back a rune that is split across writes.
+type stream struct {
+	masker  masker
+	pending []byte
+}
+
+func (s *stream) next(p []byte, mask bool) string {
+	data := append(s.pending, s.masker.mask(p, mask)...)
+	s.pending = nil
+
+	// Keep an incomplete rune at the end for the next write.
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			s.pending = append([]byte{}, data[i:]...)
+			data = data[:i]
+		}
+		break
+	}
+	return string(data)
+}
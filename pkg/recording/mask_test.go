@@ -0,0 +1,32 @@
+package recording
+
+import "testing"
+
+func TestMaskKeepsEscapeSequences(t *testing.T) {
+	m := masker{}
+	in := "\x1b[38;2;255;92;0mcard 4242\x1b[0m \x1b]2;title 1\x07cvc 123"
+	want := "\x1b[38;2;255;92;0mcard ****\x1b[0m \x1b]2;title 1\x07cvc ***"
+	if got := string(m.mask([]byte(in), true)); got != want {
+		t.Errorf("mask(%q) = %q, want %q", in, got, want)
+	}
+}
+
+func TestMaskAcrossWrites(t *testing.T) {
+	m := masker{}
+	got := string(m.mask([]byte("\x1b[3"), true)) + string(m.mask([]byte("8;5;1m42"), true))
+	want := "\x1b[38;5;1m**"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStreamHoldsSplitRunes(t *testing.T) {
+	s := stream{}
+	arrow := []byte("→")
+	if got := s.next(append([]byte("a"), arrow[:1]...), false); got != "a" {
+		t.Errorf("first write = %q, want %q", got, "a")
+	}
+	if got := s.next(arrow[1:], false); got != "→" {
+		t.Errorf("second write = %q, want %q", got, "→")
+	}
+}
@@ -0,0 +1,174 @@
+// Package recording writes sessions to asciicast v2 files
+// (https://docs.asciinema.org/manual/asciicast/v2/) so support can replay what
+// a customer saw, with the output stream and input keys timed as they
+// happened.
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Extension is used for every recording, named after its session ID.
+const Extension = ".cast"
+
+// redactGrace keeps digits masked for a moment after redaction is turned
+// off, in case a frame rendered while it was on is flushed late.
+const redactGrace = time.Second
+
+type contextKey struct{}
+
+var recorderKey = contextKey{}
+
+// Header is the first line of an asciicast file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder appends the events of one session to its file. A nil Recorder
+// records nothing, so callers don't need to check whether recording is on.
+type Recorder struct {
+	mu           sync.Mutex
+	file         *os.File
+	start        time.Time
+	output       stream
+	input        stream
+	redacted     bool
+	redactedTill time.Time
+}
+
+// New creates the recording for a session in dir.
+func New(dir, sessionID string, width, height int, term string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, sessionID+Extension), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	header, err := json.Marshal(Header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Title:     sessionID,
+		Env:       map[string]string{"TERM": term},
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Write(append(header, '\n')); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &Recorder{file: file, start: start}, nil
+}
+
+// Env is the variable an SSH client sets to have its session recorded, e.g.
+// `ssh -o SetEnv=TERMINAL_RECORD=1 terminal.shop`. The browser terminal
+// takes the same opt-in as the record query parameter.
+const Env = "TERMINAL_RECORD"
+
+// Requested is true when a session's opt-in value asks for it to be
+// recorded. Sessions are never recorded otherwise.
+func Requested(value string) bool {
+	on, _ := strconv.ParseBool(value)
+	return on
+}
+
+// WithRecorder returns a copy of ctx carrying r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey, r)
+}
+
+// FromContext returns the recorder carried by ctx, or nil.
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey).(*Recorder)
+	return r
+}
+
+// SetRedacted turns masking of every digit, in both directions, on or off.
+// The TUI turns it on while sensitive fields such as the card number and CVC
+// are on screen.
+func (r *Recorder) SetRedacted(redacted bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.redacted && !redacted {
+		r.redactedTill = time.Now().Add(redactGrace)
+	}
+	r.redacted = redacted
+}
+
+// Output records what was written to the terminal.
+func (r *Recorder) Output(p []byte) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("o", r.output.next(p, r.masking()))
+}
+
+// Input records keys read from the terminal.
+func (r *Recorder) Input(p []byte) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("i", r.input.next(p, r.masking()))
+}
+
+// Resize records a change of terminal size.
+func (r *Recorder) Resize(width, height int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recorder) masking() bool {
+	return r.redacted || time.Now().Before(r.redactedTill)
+}
+
+func (r *Recorder) event(kind, data string) {
+	if data == "" {
+		return
+	}
+	elapsed := strconv.FormatFloat(time.Since(r.start).Seconds(), 'f', 6, 64)
+	encoded, err := json.Marshal([]any{json.RawMessage(elapsed), kind, data})
+	if err != nil {
+		return
+	}
+	// A failed write loses one event; it must never break the session.
+	_, _ = r.file.Write(append(encoded, '\n'))
+}
@@ -0,0 +1,160 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event is one timed line of a recording: "o" for output, "i" for input and
+// "r" for a resize to "WxH".
+type Event struct {
+	Time float64
+	Kind string
+	Data string
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	fields := []any{&e.Time, &e.Kind, &e.Data}
+	return json.Unmarshal(data, &fields)
+}
+
+// Info describes a recording on disk.
+type Info struct {
+	SessionID string
+	Path      string
+	Started   time.Time
+	Duration  time.Duration
+	Size      int64
+}
+
+// List returns the recordings in dir, newest first.
+func List(dir string) ([]Info, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []Info{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), Extension) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		header, events, err := Read(path)
+		if err != nil {
+			continue
+		}
+		info := Info{
+			SessionID: strings.TrimSuffix(entry.Name(), Extension),
+			Path:      path,
+			Started:   time.Unix(header.Timestamp, 0),
+		}
+		if len(events) > 0 {
+			info.Duration = time.Duration(events[len(events)-1].Time * float64(time.Second))
+		}
+		if stat, err := entry.Info(); err == nil {
+			info.Size = stat.Size()
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Started.After(infos[j].Started)
+	})
+	return infos, nil
+}
+
+// Find returns the path of a recording given its session ID, a unique prefix
+// of one, or a path.
+func Find(dir, id string) (string, error) {
+	if _, err := os.Stat(id); err == nil {
+		return id, nil
+	}
+
+	infos, err := List(dir)
+	if err != nil {
+		return "", err
+	}
+	matches := []string{}
+	for _, info := range infos {
+		if strings.HasPrefix(info.SessionID, id) {
+			matches = append(matches, info.Path)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no recording for %q", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches %d recordings", id, len(matches))
+	}
+}
+
+// Read parses a recording. A truncated last line, left by a crash mid-write,
+// is skipped.
+func Read(path string) (Header, []Event, error) {
+	var header Header
+	file, err := os.Open(path)
+	if err != nil {
+		return header, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		return header, nil, fmt.Errorf("%s is empty", path)
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return header, nil, fmt.Errorf("%s has an invalid header: %w", path, err)
+	}
+	if header.Version != 2 {
+		return header, nil, fmt.Errorf("%s is asciicast v%d, only v2 is supported", path, header.Version)
+	}
+
+	events := []Event{}
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return header, events, scanner.Err()
+}
+
+// Prune deletes recordings in dir last written more than maxAge ago and
+// returns how many it deleted.
+func Prune(dir string, maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	cutoff := time.Now().Add(-maxAge)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), Extension) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -7,7 +7,9 @@ import (
 	"reflect"
 )
 
-type resource struct {
+// Resources are the values SST links to the service through
+// SST_RESOURCE_<Name> environment variables, each holding a JSON object.
+type Resources struct {
 	Api struct {
 		Url string `json:"url"`
 	}
@@ -29,20 +31,23 @@ type resource struct {
 	}
 }
 
-var Resource resource
-
-func init() {
-	val := reflect.ValueOf(&Resource).Elem()
+// Load reads every SST_RESOURCE_* variable that is set. Missing variables
+// leave their resource empty, so running outside of SST is not an error;
+// deciding what is required is up to the config loader.
+func Load() (Resources, error) {
+	var resources Resources
+	val := reflect.ValueOf(&resources).Elem()
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
 		typeField := val.Type().Field(i)
 		envVarName := fmt.Sprintf("SST_RESOURCE_%s", typeField.Name)
 		envValue, exists := os.LookupEnv(envVarName)
 		if !exists {
-			panic(fmt.Sprintf("Environment variable %s is required", envVarName))
+			continue
 		}
 		if err := json.Unmarshal([]byte(envValue), field.Addr().Interface()); err != nil {
-			panic(err)
+			return resources, fmt.Errorf("%s is not valid JSON: %w", envVarName, err)
 		}
 	}
+	return resources, nil
 }
@@ -0,0 +1,193 @@
+// Package sessions tracks the live sessions of a server, SSH and browser
+// alike, so operators can list them, message them and kick them.
+package sessions
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type contextKey struct{}
+
+var sessionKey = contextKey{}
+
+// queueSize bounds the messages waiting for a slow session, beyond which new
+// ones are dropped rather than blocking the sender.
+const queueSize = 16
+
+// Status is sent to every TUI when it starts and whenever an operator
+// changes it. During maintenance the shop can be browsed but not checked
+// out; Reason explains why and Splash holds new sessions on a notice.
+type Status struct {
+	Banner      string `yaml:"banner"`
+	Maintenance bool   `yaml:"maintenance"`
+	Reason      string `yaml:"reason"`
+	Splash      bool   `yaml:"splash"`
+}
+
+// Session is one connection. Its fields are set when it's registered, the
+// page is kept up to date by the TUI.
+type Session struct {
+	ID          string
+	Fingerprint string
+	IP          string
+	Anonymous   bool
+	Web         bool
+	Command     []string
+	Started     time.Time
+
+	mu    sync.Mutex
+	page  string
+	queue chan any
+	close func()
+}
+
+// New returns a session that close disconnects.
+func New(id, fingerprint, ip string, close func()) *Session {
+	return &Session{
+		ID:          id,
+		Fingerprint: fingerprint,
+		IP:          ip,
+		Started:     time.Now(),
+		close:       close,
+	}
+}
+
+// WithSession returns a copy of ctx carrying s.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey, s)
+}
+
+// FromContext returns the session carried by ctx, or nil.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey).(*Session)
+	return s
+}
+
+// SetPage records the page the session is on. It does nothing on a nil
+// session, e.g. the local cli.
+func (s *Session) SetPage(page string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.page = page
+}
+
+func (s *Session) Page() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.page
+}
+
+// Attach delivers messages sent to the session to its TUI through send, in
+// order, until ctx is done.
+func (s *Session) Attach(ctx context.Context, send func(msg any)) {
+	queue := make(chan any, queueSize)
+	s.mu.Lock()
+	s.queue = queue
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-queue:
+				send(msg)
+			}
+		}
+	}()
+}
+
+// Send queues msg for the session's TUI, dropping it if there is none or it
+// isn't keeping up.
+func (s *Session) Send(msg any) {
+	s.mu.Lock()
+	queue := s.queue
+	s.mu.Unlock()
+	if queue == nil {
+		return
+	}
+	select {
+	case queue <- msg:
+	default:
+	}
+}
+
+// Kick disconnects the session.
+func (s *Session) Kick() {
+	s.close()
+}
+
+// Registry is the set of live sessions and the status shown to them.
+type Registry struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	status   Status
+}
+
+func NewRegistry() *Registry {
+	return &Registry{sessions: map[string]*Session{}}
+}
+
+func (r *Registry) Add(s *Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[s.ID] = s
+}
+
+func (r *Registry) Remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// List returns the live sessions, oldest first.
+func (r *Registry) List() []*Session {
+	r.mu.Lock()
+	list := make([]*Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		list = append(list, s)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Started.Before(list[j].Started)
+	})
+	return list
+}
+
+// Find returns the sessions whose ID starts with prefix.
+func (r *Registry) Find(prefix string) []*Session {
+	matches := []*Session{}
+	for _, s := range r.List() {
+		if strings.HasPrefix(s.ID, prefix) {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+func (r *Registry) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// SetStatus updates the status and sends it to every session.
+func (r *Registry) SetStatus(update func(status *Status)) Status {
+	r.mu.Lock()
+	update(&r.status)
+	status := r.status
+	r.mu.Unlock()
+
+	for _, s := range r.List() {
+		s.Send(status)
+	}
+	return status
+}
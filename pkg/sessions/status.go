@@ -0,0 +1,74 @@
+package sessions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// statusPollInterval is how often the status file is checked for changes.
+const statusPollInterval = 5 * time.Second
+
+// LoadStatus reads a YAML status file, e.g.
+//
+//	maintenance: true
+//	reason: payments are degraded
+//	splash: true
+//	banner: orders placed today ship monday
+func LoadStatus(path string) (Status, error) {
+	var status Status
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return status, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&status); err != nil && !errors.Is(err, io.EOF) {
+		return status, err
+	}
+	return status, nil
+}
+
+// WatchStatusFile applies the status file at path now and whenever it
+// changes, until ctx is done. A change made with an admin command lasts until
+// the file changes again.
+func (r *Registry) WatchStatusFile(ctx context.Context, path string) {
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+
+	var modified time.Time
+	for {
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			if !modified.IsZero() {
+				slog.Info("Status file removed, clearing status", "path", path)
+				r.SetStatus(func(status *Status) { *status = Status{} })
+				modified = time.Time{}
+			}
+		case err != nil:
+			slog.Error("Could not read status file", "path", path, "error", err)
+		case !info.ModTime().Equal(modified):
+			modified = info.ModTime()
+			loaded, err := LoadStatus(path)
+			if err != nil {
+				slog.Error("Could not parse status file", "path", path, "error", err)
+				break
+			}
+			slog.Info("Status file changed", "path", path, "maintenance", loaded.Maintenance, "banner", loaded.Banner)
+			r.SetStatus(func(status *Status) { *status = loaded })
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -76,6 +76,9 @@ func (m model) AccountSwitch() (model, tea.Cmd) {
 	m.state.tokens = tokensState{
 		selected: 0,
 	}
+	m.state.keys = keysState{
+		selected: 0,
+	}
 	m.state.apps = appsState{
 		selected:   0,
 		submitting: false,
@@ -93,9 +96,36 @@ func (m model) AccountSwitch() (model, tea.Cmd) {
 	m = m.updateAccountViewports()
 	m.state.account.menuViewport.GotoTop()
 	m.state.account.detailViewport.GotoTop()
+	m.state.orders.viewing = false
+	m.state.orders.filtering = false
+	m.state.orders.requesting = false
+	m.state.support.viewing = false
+	m.state.support.opening = false
+	m.state.support.replying = false
+	m.state.support.sending = false
+	m.state.support.poll++
+	// a failed first page is tried again the next time the account opens
+	if !m.state.orders.loaded && !m.state.orders.loading {
+		var cmd tea.Cmd
+		m, cmd = m.loadOrders("")
+		return m, tea.Batch(m.state.apps.form.Init(), cmd)
+	}
 	return m, m.state.apps.form.Init()
 }
 
+// AccountPageSwitch opens the account page on accountPage, focused on its
+// list when it has one.
+func (m model) AccountPageSwitch(accountPage page) (model, tea.Cmd) {
+	m, cmd := m.AccountSwitch()
+	for index, p := range m.accountPages {
+		if p == accountPage {
+			m.state.account.selected = index
+			m.state.account.focused = true
+		}
+	}
+	return m, cmd
+}
+
 func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -112,7 +142,8 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch msg.String() {
 			case "esc", "left", "h":
-				if !m.state.apps.editing && !m.state.orders.viewing {
+				if !m.state.apps.editing && !m.state.orders.viewing && !m.state.orders.filtering &&
+					!m.state.support.viewing && !m.state.support.opening {
 					s := m.state.account.selected
 					m, cmd = m.AccountSwitch()
 					cmds = append(cmds, cmd)
@@ -130,6 +161,9 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case tokensPage:
 			nextModel, cmd = m.TokensUpdate(msg)
 			handled = true
+		case keysPage:
+			nextModel, cmd = m.KeysUpdate(msg)
+			handled = true
 		case appsPage:
 			nextModel, cmd = m.AppsUpdate(msg)
 			handled = true
@@ -142,6 +176,9 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case paymentPage:
 			nextModel, cmd = m.PaymentUpdate(msg)
 			handled = true
+		case supportPage:
+			nextModel, cmd = m.SupportUpdate(msg)
+			handled = true
 		}
 
 		if handled {
@@ -160,6 +197,10 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 					if m.state.tokens.selected != nextModel.state.tokens.selected {
 						nextModel = m.scrollToAccountDetailItem(nextModel, accountPage)
 					}
+				case keysPage:
+					if m.state.keys.selected != nextModel.state.keys.selected {
+						nextModel = m.scrollToAccountDetailItem(nextModel, accountPage)
+					}
 				case appsPage:
 					if m.state.apps.selected != nextModel.state.apps.selected {
 						nextModel = m.scrollToAccountDetailItem(nextModel, accountPage)
@@ -173,6 +214,17 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 						nextModel.state.account.detailViewport.SetYOffset(nextModel.state.orders.yOffset)
 						nextModel.state.orders.yOffset = 0
 					}
+				case supportPage:
+					// keep the newest message of a conversation in view
+					if m.supportMessages() != nextModel.supportMessages() || m.state.support.replying != nextModel.state.support.replying {
+						if nextModel.state.support.viewing {
+							nextModel.state.account.detailViewport.GotoBottom()
+						} else {
+							nextModel.state.account.detailViewport.GotoTop()
+						}
+					} else if m.state.support.selected != nextModel.state.support.selected {
+						nextModel = m.scrollToAccountDetailItem(nextModel, accountPage)
+					}
 				}
 			}
 
@@ -188,10 +240,15 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "shift+tab", "up", "k":
 			return m.UpdateSelectedAccountPage(true)
 		case "enter", "right", "l":
+			if accountPage == claimPage {
+				return m.GenerateClaimCode()
+			}
 			if accountPage == subscriptionsPage ||
 				accountPage == ordersPage ||
 				accountPage == tokensPage ||
-				accountPage == appsPage {
+				accountPage == keysPage ||
+				accountPage == appsPage ||
+				accountPage == supportPage {
 				m.state.account.focused = true
 				switch accountPage {
 				case subscriptionsPage:
@@ -200,12 +257,19 @@ func (m model) AccountUpdate(msg tea.Msg) (model, tea.Cmd) {
 				case tokensPage:
 					m.state.tokens.selected = 0
 					return m.TokensUpdate(msg)
+				case keysPage:
+					m.state.keys.selected = 0
+					return m.KeysUpdate(msg)
 				case appsPage:
 					m.state.apps.selected = 0
 					return m.AppsUpdate(msg)
 				case ordersPage:
 					m.state.orders.selected = 0
 					return m.OrdersUpdate(msg)
+				case supportPage:
+					m.state.support.selected = 0
+					m.state.footer.commands = supportCommands
+					return m.loadTickets()
 				}
 
 			}
@@ -238,10 +302,16 @@ func getAccountPageName(accountPage page) string {
 	switch accountPage {
 	case ordersPage:
 		return "order history"
+	case spendingPage:
+		return "spending"
 	case subscriptionsPage:
 		return "subscriptions"
 	case tokensPage:
 		return "access tokens"
+	case keysPage:
+		return "ssh keys"
+	case claimPage:
+		return "save your cart"
 	case appsPage:
 		return "apps (oauth 2.0)"
 	case shippingPage:
@@ -250,6 +320,8 @@ func getAccountPageName(accountPage page) string {
 		return "payment methods"
 	case faqPage:
 		return "faq"
+	case supportPage:
+		return "support"
 	case aboutPage:
 		return "about"
 	}
@@ -340,16 +412,24 @@ func (m model) GetAccountPageContent(accountPage page, totalWidth int) string {
 	switch accountPage {
 	case ordersPage:
 		return m.OrdersView(totalWidth, m.state.account.focused)
+	case spendingPage:
+		return m.SpendingView(totalWidth)
 	case subscriptionsPage:
 		return m.SubscriptionsView(totalWidth, m.state.account.focused)
 	case tokensPage:
 		return m.TokensView(totalWidth, m.state.account.focused)
+	case keysPage:
+		return m.KeysView(totalWidth, m.state.account.focused)
+	case claimPage:
+		return m.ClaimView(totalWidth)
 	case appsPage:
 		return m.AppsView(totalWidth, m.state.account.focused)
 	case shippingPage:
 		return m.ShippingView(totalWidth, m.state.account.focused)
 	case faqPage:
 		return m.FaqView(totalWidth)
+	case supportPage:
+		return m.SupportView(totalWidth, m.state.account.focused)
 	case aboutPage:
 		return m.AboutView(totalWidth)
 	}
@@ -409,14 +489,22 @@ func (m model) scrollToAccountDetailItem(model model, accountPage page) model {
 		itemHeight = 7                    // Estimated height of a token item with padding
 		itemCount = len(model.tokens) + 1 // +1 for "add token" button
 		selectedIndex = model.state.tokens.selected
+	case keysPage:
+		itemHeight = 4                  // Estimated height of a key item with padding
+		itemCount = len(model.keys) + 1 // +1 for "link key" button
+		selectedIndex = model.state.keys.selected
 	case appsPage:
 		itemHeight = 8                  // Estimated height of an app item with padding
 		itemCount = len(model.apps) + 1 // +1 for "create app" button
 		selectedIndex = model.state.apps.selected
 	case ordersPage:
-		itemHeight = 4 // Reduced height for order item with just date (instead of all products)
-		itemCount = len(model.orders)
+		itemHeight = 5 // Order item with its date and ID (instead of all products)
+		itemCount = len(model.state.orders.list)
 		selectedIndex = model.state.orders.selected
+	case supportPage:
+		itemHeight = 5                     // Ticket with its order and last update
+		itemCount = len(model.tickets) + 1 // +1 for "open a ticket" button
+		selectedIndex = model.state.support.selected
 	default:
 		return model // No scrolling for other pages
 	}
@@ -482,5 +570,8 @@ func (m model) UpdateSelectedAccountPage(previous bool) (model, tea.Cmd) {
 		m.state.account.detailViewport.GotoTop()
 	}
 
+	if m.accountPages[next] == spendingPage {
+		return m.loadSpending()
+	}
 	return m, nil
 }
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -21,6 +23,7 @@ type appInput struct {
 type appsState struct {
 	selected   int
 	deleting   *int
+	removing   string
 	editing    bool
 	input      appInput
 	form       *huh.Form
@@ -106,16 +109,16 @@ func (m model) AppsFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 		m.state.apps.submitting = true
 
 		form := m.state.apps.form
-		return m, func() tea.Msg {
+		return m, m.traced("App.New", func(ctx context.Context) tea.Msg {
 			params := terminal.AppNewParams{
 				Name:        terminal.F(form.GetString("name")),
 				RedirectUri: terminal.F(form.GetString("redirectUri")),
 			}
-			response, err := m.client.App.New(m.context, params)
+			response, err := m.client.App.New(ctx, params)
 			if err != nil {
 				return err
 			}
-			apps, err := m.client.App.List(m.context)
+			apps, err := m.client.App.List(ctx)
 			if err != nil {
 				return err
 			}
@@ -126,7 +129,7 @@ func (m model) AppsFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 				newApp: response.Data,
 				apps:   apps.Data,
 			}
-		}
+		})
 	}
 
 	return m, tea.Batch(cmds...)
@@ -157,27 +160,26 @@ func (m model) AppsUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.previousApp()
 			}
 		case "delete", "d", "backspace", "x":
-			if m.state.apps.deleting == nil {
+			if m.state.apps.deleting == nil && m.state.apps.removing == "" {
 				m.state.apps.deleting = &m.state.apps.selected
 			}
 			return m, nil
 		case "y":
 			if m.state.apps.deleting != nil {
 				m.state.apps.deleting = nil
-				_, err := m.client.App.Delete(m.context, m.apps[m.state.apps.selected].ID)
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-				if len(m.apps)-1 == 0 {
-					m.state.account.focused = false
-				}
-				return m, func() tea.Msg {
-					apps, err := m.client.App.List(m.context)
+				id := m.apps[m.state.apps.selected].ID
+				m.state.apps.removing = id
+				return m, m.traced("App.Delete", func(ctx context.Context) tea.Msg {
+					_, err := m.client.App.Delete(ctx, id)
+					if err != nil {
+						return err
+					}
+					apps, err := m.client.App.List(ctx)
 					if err != nil {
 						return err
 					}
 					return apps.Data
-				}
+				})
 			}
 			return m, nil
 		case "n", "esc":
@@ -223,6 +225,9 @@ func (m model) AppsView(totalWidth int, focused bool) string {
 		if m.state.apps.deleting != nil && *m.state.apps.deleting == i {
 			content = accent("are you sure you want to remove?") + base(" (y/n)")
 		}
+		if app.ID == m.state.apps.removing {
+			content = base("removing...")
+		}
 		box := m.CreateBoxCustom(
 			content,
 			focused && i == m.state.apps.selected,
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type cartState struct {
@@ -115,12 +117,12 @@ func (m model) UpdateCart(productVariantID string, offset int64) (model, tea.Cmd
 	m.cart.Subtotal = m.CalculateSubtotal()
 	m.state.cart.lastUpdateID = updateID
 
-	return m, func() tea.Msg {
+	return m, m.traced("Cart.SetItem", func(ctx context.Context) tea.Msg {
 		params := terminal.CartSetItemParams{
 			ProductVariantID: terminal.String(cartItem.ProductVariantID),
 			Quantity:         terminal.Int(next),
 		}
-		response, err := m.client.Cart.SetItem(m.context, params)
+		response, err := m.client.Cart.SetItem(ctx, params)
 		if err != nil {
 			return err
 		}
@@ -128,7 +130,7 @@ func (m model) UpdateCart(productVariantID string, offset int64) (model, tea.Cmd
 			updateID: updateID,
 			updated:  response.Data,
 		}
-	}
+	})
 }
 
 func (m model) UpdateSelectedCartItem(previous bool) (model, tea.Cmd) {
@@ -147,7 +149,7 @@ func (m model) UpdateSelectedCartItem(previous bool) (model, tea.Cmd) {
 		next = 0
 	}
 
-	max := m.CartItemCount() - 1
+	max := m.CartItemCount() + len(m.codes) - 1
 	if next > max {
 		next = max
 	}
@@ -159,17 +161,48 @@ func (m model) UpdateSelectedCartItem(previous bool) (model, tea.Cmd) {
 func (m model) CartSwitch() (model, tea.Cmd) {
 	m = m.SwitchPage(cartPage)
 	m.state.subscribe.product = nil
+	m.state.codes.entering = false
 	m.state.footer.commands = []footerCommand{
 		{key: "esc", value: "back"},
 		{key: "↑/↓", value: "items"},
 		{key: "+/-", value: "qty"},
+		{key: "p", value: "promo code"},
 		{key: "c", value: "checkout"},
 	}
 
 	return m, nil
 }
 
+// selectedCode is the code selected below the cart items, if any.
+func (m model) selectedCode() *api.CartCode {
+	index := m.state.cart.selected - m.CartItemCount()
+	if index < 0 || index >= len(m.codes) {
+		return nil
+	}
+	return &m.codes[index]
+}
+
 func (m model) CartUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case CartCodesMsg:
+		m.state.codes.removing = ""
+		if m.state.codes.applying {
+			m.state.codes.applying = false
+			return m.CartSwitch()
+		}
+		m.state.cart.selected = min(m.state.cart.selected, max(m.CartItemCount()+len(m.codes)-1, 0))
+		return m, nil
+	case CartCodeRejectedMsg:
+		m.state.codes.applying = false
+		m.state.codes.error = msg.message
+		m.state.codes.form = m.createCodeForm()
+		return m, m.state.codes.form.Init()
+	}
+
+	if m.state.codes.entering {
+		return m.CodeEntryUpdate(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -178,17 +211,27 @@ func (m model) CartUpdate(msg tea.Msg) (model, tea.Cmd) {
 		case "k", "up", "shift+tab":
 			return m.UpdateSelectedCartItem(true)
 		case "+", "=", "right", "l":
-			if m.IsCartEmpty() {
+			if m.state.cart.selected >= m.CartItemCount() {
 				return m, nil
 			}
 			productVariantID := m.VisibleCartItems()[m.state.cart.selected].ProductVariantID
 			return m.UpdateCart(productVariantID, 1)
 		case "-", "left", "h":
-			if m.IsCartEmpty() {
+			if m.state.cart.selected >= m.CartItemCount() {
 				return m, nil
 			}
 			productVariantID := m.VisibleCartItems()[m.state.cart.selected].ProductVariantID
 			return m.UpdateCart(productVariantID, -1)
+		case "p":
+			if m.IsCartEmpty() {
+				return m, nil
+			}
+			return m.CodeEntrySwitch()
+		case "x", "delete", "backspace", "d":
+			if code := m.selectedCode(); code != nil && m.state.codes.removing == "" {
+				return m.removeCode(code.Code)
+			}
+			return m, nil
 		case "enter", "c":
 			if m.IsCartEmpty() {
 				return m, nil
@@ -253,6 +296,8 @@ func (m model) CartView() string {
 		lines = append(lines, line)
 	}
 
+	lines = append(lines, m.CodesView())
+
 	return m.theme.Base().Render(lipgloss.JoinVertical(
 		lipgloss.Left,
 		lines...,
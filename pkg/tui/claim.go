@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+type ClaimCodeMsg struct {
+	code api.ClaimCode
+}
+
+type claimState struct {
+	generating bool
+	code       *api.ClaimCode
+}
+
+func (m model) GenerateClaimCode() (model, tea.Cmd) {
+	if m.state.claim.generating {
+		return m, nil
+	}
+
+	m.state.claim.generating = true
+	return m, m.traced("Claim.New", func(ctx context.Context) tea.Msg {
+		code, err := api.NewClaimCode(ctx, m.client)
+		if err != nil {
+			return err
+		}
+		return ClaimCodeMsg{code: *code}
+	})
+}
+
+func (m model) ClaimView(totalWidth int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	lines := []string{}
+	lines = append(lines, accent("you're shopping without an ssh key"))
+	lines = append(lines, "")
+	lines = append(lines, base(wordWrap("your cart and profile disappear when you disconnect. claim them with a public key to keep them and to subscribe.", totalWidth)))
+	lines = append(lines, "")
+
+	switch {
+	case m.state.claim.generating:
+		lines = append(lines, base("generating claim code..."))
+	case m.state.claim.code != nil:
+		lines = append(lines, base(wordWrap("before you disconnect, run this from another terminal:", totalWidth)))
+		lines = append(lines, "")
+		lines = append(lines, m.theme.TextBrand().Bold(true).Render("ssh -i <key> terminal.shop claim "+m.state.claim.code.Code))
+		lines = append(lines, "")
+		lines = append(lines, accent("expires: ")+base(m.state.claim.code.Expires))
+	default:
+		lines = append(lines, m.theme.TextAccent().Bold(true).Render("enter")+base(" get a claim code"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
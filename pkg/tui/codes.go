@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+type codesState struct {
+	entering bool
+	applying bool
+	removing string
+	input    string
+	form     *huh.Form
+	// error is why the API turned the last code down, shown under the form
+	// rather than as a page error so the customer can fix a typo.
+	error string
+}
+
+type CartCodesMsg struct {
+	codes []api.CartCode
+}
+
+type CartCodeRejectedMsg struct {
+	message string
+}
+
+// LoadCartCodesCmd loads the codes already on the cart. It runs while the
+// shop loads, which a failure here shouldn't stop, so it's only logged and
+// the cart shows no codes.
+func (m model) LoadCartCodesCmd() tea.Cmd {
+	return m.traced("Cart.Codes", func(ctx context.Context) tea.Msg {
+		codes, err := api.ListCartCodes(ctx, m.client)
+		if err != nil {
+			logger.FromContext(ctx).Warn("could not load cart codes", "error", err)
+			return nil
+		}
+		return CartCodesMsg{codes: codes}
+	})
+}
+
+// Totals is what the order being checked out costs with its codes applied.
+// Codes only apply to the cart, not to subscriptions.
+func (m model) Totals() api.Totals {
+	if m.IsSubscribing() {
+		price := m.state.subscribe.product.Variants[m.state.subscribe.selected].Price
+		return api.NewTotals(price, 0, taxAmount(m.Tax()), nil)
+	}
+	return api.NewTotals(m.cart.Amount.Subtotal, m.cart.Amount.Shipping, taxAmount(m.Tax()), m.codes)
+}
+
+func (m model) createCodeForm() *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("promo or gift card code").
+				Key("code").
+				Value(&m.state.codes.input).
+				Validate(validate.Compose(
+					validate.NotEmpty("code"),
+					validate.WithinLen(1, 32, "code"),
+					validate.IsCode("code"),
+				)),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+}
+
+func (m model) CodeEntrySwitch() (model, tea.Cmd) {
+	m.state.codes.entering = true
+	m.state.codes.error = ""
+	m.state.codes.input = ""
+	m.state.codes.form = m.createCodeForm()
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "apply"},
+	}
+	return m, m.state.codes.form.Init()
+}
+
+func (m model) CodeEntryUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.state.codes.entering = false
+			return m.CartSwitch()
+		}
+	}
+
+	next, cmd := m.state.codes.form.Update(msg)
+	m.state.codes.form = next.(*huh.Form)
+	if !m.state.codes.applying && m.state.codes.form.State == huh.StateCompleted {
+		m.state.codes.applying = true
+		code := strings.ToUpper(strings.TrimSpace(m.state.codes.form.GetString("code")))
+		return m, m.traced("Cart.ApplyCode", func(ctx context.Context) tea.Msg {
+			if err := api.ApplyCartCode(ctx, m.client, code); err != nil {
+				return CartCodeRejectedMsg{message: api.GetErrorMessage(err)}
+			}
+			codes, err := api.ListCartCodes(ctx, m.client)
+			if err != nil {
+				return err
+			}
+			return CartCodesMsg{codes: codes}
+		})
+	}
+	return m, cmd
+}
+
+func (m model) removeCode(code string) (model, tea.Cmd) {
+	m.state.codes.removing = code
+	return m, m.traced("Cart.RemoveCode", func(ctx context.Context) tea.Msg {
+		if err := api.RemoveCartCode(ctx, m.client, code); err != nil {
+			return err
+		}
+		codes, err := api.ListCartCodes(ctx, m.client)
+		if err != nil {
+			return err
+		}
+		return CartCodesMsg{codes: codes}
+	})
+}
+
+func formatCode(code api.CartCode) string {
+	switch code.Kind {
+	case api.CodeGiftCard:
+		return "gift card " + code.Code
+	default:
+		return "promo " + code.Code
+	}
+}
+
+func (m model) CodesView() string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	lines := []string{}
+	if m.state.codes.entering {
+		if m.state.codes.applying {
+			lines = append(lines, base("applying code..."))
+		} else {
+			lines = append(lines, m.state.codes.form.WithWidth(m.widthContent-4).View())
+		}
+		if m.state.codes.error != "" {
+			lines = append(lines, m.theme.TextError().Render(wordWrap(m.state.codes.error, m.widthContent-4)))
+		}
+	}
+
+	for i, code := range m.codes {
+		selected := m.state.cart.selected == m.CartItemCount()+i
+		amount := "-" + formatUSD(int(code.Amount))
+		if code.Kind == api.CodeGiftCard {
+			amount = "balance " + formatUSD(int(code.Amount))
+		}
+		name := accent(formatCode(code))
+		space := m.widthContent - lipgloss.Width(name) - lipgloss.Width(amount) - 4
+		content := lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			name,
+			m.theme.Base().Width(max(space, 1)).Render(),
+			base(amount),
+		)
+		if code.Description != "" {
+			content = lipgloss.JoinVertical(lipgloss.Left, content, base(code.Description))
+		}
+		if code.Code == m.state.codes.removing {
+			content = base("removing...")
+		}
+		lines = append(lines, m.CreateBox(content, selected))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// costLine is one line of the cost breakdown shown at each step of checkout.
+type costLine struct {
+	label  string
+	amount string
+	accent bool
+}
+
+// costLines breaks down the cost of the order, with a line for tax and for
+// each kind of code only when one applies.
+func (m model) costLines() []costLine {
+	totals := m.Totals()
+	lines := []costLine{
+		{label: "subtotal", amount: formatUSD(int(totals.Subtotal))},
+		{label: "shipping", amount: formatUSD(int(totals.Shipping))},
+	}
+	if totals.Discount > 0 {
+		lines = append(lines, costLine{label: "discount", amount: "-" + formatUSD(int(totals.Discount))})
+	}
+	if tax := m.Tax(); tax != nil {
+		lines = append(lines, costLine{label: tax.Label(), amount: formatUSD(int(tax.Amount))})
+	}
+	if totals.GiftCard == 0 {
+		return append(lines, costLine{label: "total", amount: formatUSD(int(totals.Total)), accent: true})
+	}
+	return append(lines,
+		costLine{label: "total", amount: formatUSD(int(totals.Total))},
+		costLine{label: "gift card", amount: "-" + formatUSD(int(totals.GiftCard))},
+		costLine{label: "due", amount: formatUSD(int(totals.Due)), accent: true},
+	)
+}
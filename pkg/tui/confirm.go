@@ -1,60 +1,178 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/google/uuid"
 	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
 )
 
 type confirmState struct {
 	submitting bool
+	// idempotencyKey identifies this checkout to the API, so submitting again
+	// after an error can't place a second order.
+	idempotencyKey string
+	// noting is true while the note form is open. The note itself is kept
+	// by the API on the cart.
+	noting bool
+	saving bool
+	note   string
+	form   *huh.Form
+}
+
+type NoteSavedMsg struct {
+	cart terminal.Cart
 }
 
 func (m model) ConfirmSwitch() (model, tea.Cmd) {
 	m = m.SwitchPage(confirmPage)
 	m.state.confirm.submitting = false
+	m.state.confirm.noting = false
+	m.state.vat.editing = false
+	m.state.confirm.idempotencyKey = uuid.NewString()
 	m.state.footer.commands = []footerCommand{
 		{key: "esc", value: "back"},
 		{key: "enter", value: "next"},
 	}
+	if !m.IsSubscribing() {
+		m.state.footer.commands = append(m.state.footer.commands, footerCommand{key: "n", value: "note"})
+	}
+	if m.canSetVATID() {
+		m.state.footer.commands = append(m.state.footer.commands, footerCommand{key: "v", value: "vat id"})
+	}
 	return m, nil
 }
 
+func (m model) NoteSwitch() (model, tea.Cmd) {
+	m.state.confirm.noting = true
+	m.state.confirm.note = api.CartNote(m.cart)
+	m.state.confirm.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("note for the shop").
+				Key("note").
+				CharLimit(500).
+				Value(&m.state.confirm.note).
+				Validate(validate.WithinLen(0, 500, "note")),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithWidth(m.widthContent - 2).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "save"},
+	}
+	return m, m.state.confirm.form.Init()
+}
+
+func (m model) NoteUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			return m.ConfirmSwitch()
+		}
+	}
+
+	next, cmd := m.state.confirm.form.Update(msg)
+	m.state.confirm.form = next.(*huh.Form)
+	if !m.state.confirm.saving && m.state.confirm.form.State == huh.StateCompleted {
+		m.state.confirm.saving = true
+		m.state.confirm.note = m.state.confirm.form.GetString("note")
+		note := strings.TrimSpace(m.state.confirm.note)
+		return m, m.traced("Cart.SetNote", func(ctx context.Context) tea.Msg {
+			if err := api.SetCartNote(ctx, m.client, note); err != nil {
+				return err
+			}
+			cart, err := m.client.Cart.Get(ctx)
+			if err != nil {
+				return err
+			}
+			return NoteSavedMsg{cart: cart.Data}
+		})
+	}
+	return m, cmd
+}
+
 func (m model) ConfirmUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case NoteSavedMsg:
+		m.cart = msg.cart
+		m.state.confirm.saving = false
+		return m.ConfirmSwitch()
+	case VATIDSavedMsg:
+		m.cart = msg.cart
+		m.state.vat.saving = false
+		return m.ConfirmSwitch()
+	}
+
+	if m.state.confirm.noting {
+		return m.NoteUpdate(msg)
+	}
+	if m.state.vat.editing {
+		return m.VATIDUpdate(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "n":
+			if m.IsSubscribing() || m.state.confirm.submitting {
+				return m, nil
+			}
+			return m.NoteSwitch()
+		case "v":
+			if !m.canSetVATID() || m.state.confirm.submitting {
+				return m, nil
+			}
+			return m.VATIDSwitch()
 		case "esc":
+			if m.paidByGiftCard() {
+				return m.ShippingSwitch()
+			}
 			return m.PaymentSwitch()
 		case "enter":
+			if m.status.Maintenance {
+				return m, nil
+			}
 			m.state.confirm.submitting = true
-			return m, func() tea.Msg {
-				if m.IsSubscribing() {
+			if m.IsSubscribing() {
+				return m, m.traced("Subscription.New", func(ctx context.Context) tea.Msg {
 					m.subscription.Quantity = terminal.Int(1)
 					params := terminal.SubscriptionNewParams{Subscription: m.subscription}
-					subscription, err := m.client.Subscription.New(m.context, params)
+					subscription, err := m.client.Subscription.New(ctx, params, api.WithIdempotencyKey(m.state.confirm.idempotencyKey))
 					if err != nil {
 						return err
 					}
 					return subscription
-				} else {
-					order, err := m.client.Cart.Convert(m.context)
-					if err != nil {
-						return err
-					}
-					return order.Data
-				}
+				})
 			}
+			return m, m.traced("Cart.Convert", func(ctx context.Context) tea.Msg {
+				order, err := m.client.Cart.Convert(ctx, api.WithIdempotencyKey(m.state.confirm.idempotencyKey))
+				if err != nil {
+					return err
+				}
+				return order.Data
+			})
 		}
 	case error:
 		m.state.confirm.submitting = false
 		return m, nil
 	case terminal.Order:
 		m.order = &msg
+		m.state.final = finalState{
+			service:   m.cart.Shipping.Service,
+			timeframe: m.cart.Shipping.Timeframe,
+		}
 		return m.FinalSubSwitch()
 	case *terminal.SubscriptionNewResponse:
+		m.order = nil
 		return m.FinalSwitch()
 	}
 	return m, nil
@@ -62,7 +180,21 @@ func (m model) ConfirmUpdate(msg tea.Msg) (model, tea.Cmd) {
 
 func (m model) ConfirmView() string {
 	if m.state.confirm.submitting {
-		return m.theme.Base().Width(m.widthContent).Render(" submitting order...")
+		text := " submitting order..."
+		if m.state.retrying != nil {
+			text += "\n " + m.RetryingView()
+		}
+		return m.theme.Base().Width(m.widthContent).Render(text)
+	}
+
+	if m.state.confirm.saving {
+		return m.theme.Base().Width(m.widthContent).Render(" saving note...")
+	}
+	if m.state.confirm.noting {
+		return m.theme.Base().Padding(0, 1).Render(m.state.confirm.form.View())
+	}
+	if m.state.vat.editing {
+		return m.VATIDView()
 	}
 
 	card := m.GetSelectedCard()
@@ -78,6 +210,9 @@ func (m model) ConfirmView() string {
 		view.WriteString("\nmonthly subscription\n")
 		view.WriteString("\n")
 	}
+	if gift := m.Gift(); gift != nil {
+		view.WriteString(m.GiftSummaryView(gift, m.widthContent-2) + "\n\n")
+	}
 	view.WriteString(address.Name + "\n")
 	view.WriteString(address.Street1 + "\n")
 	if address.Street2 != "" {
@@ -86,6 +221,12 @@ func (m model) ConfirmView() string {
 	view.WriteString(
 		address.City + ", " + address.Province + ", " + address.Country + " " + address.Zip + "\n",
 	)
+	if instructions := api.AddressInstructions(*address); instructions != "" {
+		view.WriteString(wordWrap("instructions: "+instructions, m.widthContent-2) + "\n")
+	}
+	if note := api.CartNote(m.cart); note != "" && !m.IsSubscribing() {
+		view.WriteString("\n" + wordWrap("note: "+note, m.widthContent-2) + "\n")
+	}
 	if !m.IsSubscribing() {
 		view.WriteString("\n")
 		view.WriteString(m.cart.Shipping.Service + "\n")
@@ -94,31 +235,39 @@ func (m model) ConfirmView() string {
 		}
 	}
 	view.WriteString("\n")
-	view.WriteString(fmt.Sprintf("cc: %s", formatLast4(card.Last4)) + "\n")
-	var subtotal int
-	var shipping int
-	if m.IsSubscribing() {
-		subtotal = int(m.state.subscribe.product.Variants[m.state.subscribe.selected].Price)
-		shipping = 0
+	if m.paidByGiftCard() {
+		view.WriteString("paid in full by gift card\n")
 	} else {
-		subtotal = int(m.cart.Amount.Subtotal)
-		shipping = int(m.cart.Amount.Shipping)
+		view.WriteString(fmt.Sprintf("cc: %s", formatLast4(card.Last4)) + "\n")
+	}
+	for _, line := range m.costLines() {
+		text := fmt.Sprintf("%-10s %s", line.label+":", line.amount)
+		if line.accent {
+			text = m.theme.TextAccent().Render(text)
+		}
+		view.WriteString(text + "\n")
+	}
+	if tax := m.Tax(); tax != nil && tax.VATID != "" {
+		totals := m.Totals()
+		view.WriteString("\n" + strings.Join(formatInvoice(*tax, totals.Total-totals.Tax), "\n") + "\n")
 	}
-	total := subtotal + shipping
-
-	view.WriteString(fmt.Sprintf("subtotal: %s", formatUSD(subtotal)) + "\n")
-	view.WriteString(fmt.Sprintf("shipping: %s", formatUSD(shipping)) + "\n")
-	view.WriteString(
-		m.theme.TextAccent().
-			Render(fmt.Sprintf("total:    %s", formatUSD(total)) + "\n"),
-	)
 	view.WriteString("\n")
-	view.WriteString(m.theme.TextBrand().Render("press enter to confirm") + "\n")
+	if m.status.Maintenance {
+		view.WriteString(m.theme.TextError().Render(wordWrap(m.maintenanceNotice(), m.widthContent-2)) + "\n")
+	} else {
+		view.WriteString(m.theme.TextBrand().Render("press enter to confirm") + "\n")
+	}
 	view.WriteString("\n")
 
 	return m.theme.Base().Padding(0, 1).Render(view.String())
 }
 
+// paidByGiftCard is true when gift cards cover the whole order, which then
+// needs no card.
+func (m model) paidByGiftCard() bool {
+	return !m.IsSubscribing() && len(m.codes) > 0 && m.Totals().Due == 0
+}
+
 func formatUSD(cents int) string {
 	dollars := cents / 100
 	remainingCents := cents % 100
@@ -0,0 +1,21 @@
+package tui
+
+import "testing"
+
+func TestNoteForm(t *testing.T) {
+	tm := newTestModel(t, nil)
+	tm.m = tm.m.SwitchPage(confirmPage)
+	tm.switchTo(tm.m.NoteSwitch())
+	tm.press("  no rush, it's a gift  ", "enter")
+
+	request, ok := tm.api.request("PUT", "/cart/note")
+	if !ok {
+		t.Fatal("the note wasn't saved")
+	}
+	if want := `{"note":"no rush, it's a gift"}`; request.body != want {
+		t.Errorf("got %s, want %s", request.body, want)
+	}
+	if tm.m.state.confirm.noting {
+		t.Error("the note form is still open once saved")
+	}
+}
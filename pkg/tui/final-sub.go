@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -25,6 +26,8 @@ func (m model) FinalSubSwitch() (model, tea.Cmd) {
 	}
 	m.cart.Items = []terminal.CartItem{}
 	m.cart.Subtotal = 0
+	m.cart.JSON.ExtraFields = nil // the gift went with the order
+	m.codes = nil
 
 	m.state.finalSub.weeks = 3
 	m.state.finalSub.submitting = false
@@ -57,7 +60,7 @@ func (m model) FinalSubUpdate(msg tea.Msg) (model, tea.Cmd) {
 			}
 
 			m.state.finalSub.submitting = true
-			return m, func() tea.Msg {
+			return m, m.traced("Subscription.New", func(ctx context.Context) tea.Msg {
 				for _, item := range m.order.Items {
 					subscription := terminal.SubscriptionParam{
 						Quantity:         terminal.F(item.Quantity),
@@ -72,14 +75,14 @@ func (m model) FinalSubUpdate(msg tea.Msg) (model, tea.Cmd) {
 						CardID:    terminal.F(m.cart.CardID),
 					}
 					params := terminal.SubscriptionNewParams{Subscription: subscription}
-					_, err := m.client.Subscription.New(m.context, params)
+					_, err := m.client.Subscription.New(ctx, params)
 					if err != nil {
 						return err
 					}
 				}
 
 				return SubscriptionCompleteMsg{}
-			}
+			})
 
 		case "+", "=", "l", "up", "right":
 			if m.state.finalSub.complete {
@@ -1,20 +1,50 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/qrfefe"
 )
 
+type finalState struct {
+	// service and timeframe are the shipping quote the order was placed
+	// with, which the order itself doesn't carry until it ships.
+	service   string
+	timeframe string
+}
+
 func (m model) FinalSwitch() (model, tea.Cmd) {
 	m = m.SwitchPage(finalPage)
-	m.state.footer.commands = []footerCommand{
-		{key: "enter", value: "done"},
-	}
 	m.cart.Items = []terminal.CartItem{}
 	m.cart.Subtotal = 0
+
+	if m.order == nil {
+		m.state.footer.commands = []footerCommand{
+			{key: "enter", value: "view subscriptions"},
+			{key: "esc", value: "back to shop"},
+		}
+		return m, m.traced("Subscription.List", func(ctx context.Context) tea.Msg {
+			subscriptions, err := m.client.Subscription.List(ctx)
+			if err != nil {
+				return err
+			}
+			return subscriptions.Data
+		})
+	}
+
+	m.state.footer.commands = []footerCommand{
+		{key: "enter", value: "view order"},
+		{key: "esc", value: "back to shop"},
+	}
+	// the account pages through orders again with the new one
+	m.state.orders.loaded = false
+	m.state.spending.loaded = false
 	return m, nil
 }
 
@@ -22,18 +52,119 @@ func (m model) FinalUpdate(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "enter", "esc":
-			return m, tea.Quit
+		case "enter":
+			if m.order == nil {
+				return m.AccountPageSwitch(subscriptionsPage)
+			}
+			return m.OrderDetailSwitch(*m.order)
+		case "esc":
+			return m.ShopSwitch()
 		}
 	}
 	return m, nil
 }
 
+// orderURL is the carrier's tracking page once the order has shipped and the
+// shop's own order page until then, if one is configured.
+func orderURL(order *terminal.Order) string {
+	if order.Tracking.URL != "" {
+		return order.Tracking.URL
+	}
+	return api.OrderStatusURL(order.ID)
+}
+
 func (m model) FinalView() string {
-	return m.theme.Base().Width(m.widthContent).Padding(0, 1).Render(lipgloss.JoinVertical(
-		lipgloss.Left,
-		m.theme.TextAccent().Render("Thank you for ordering with Terminal Products, Inc.")),
-		"\n\nAt this very moment as you sit, stunned and in awe of the CLI experience that just befell you, a personalised order confirmation email is on its way to your inbox.\n\nSimultaneously, news of your order is being celebrated wildly by the team. Perhaps too wildly by some. Once the excitement of your order has subsided to manageable levels your order will be sealed, shipped, and tracked courtesy of our very own Chief of SST.\n\nYours sincerely,\n\nDax, Adam, Prime, Teej, David\n\nTerminal Products, Inc.",
-		fmt.Sprintf("\n\nps. %s", m.theme.TextBrand().Render("https://www.terminal.shop/xxx")),
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	if m.order == nil {
+		return m.theme.Base().Width(m.widthContent).Padding(0, 1).Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			accent("Thank you for subscribing with Terminal Products, Inc."),
+			"",
+			base(wordWrap("your subscription is set up and a confirmation is on its way to your inbox.", m.widthContent-2)),
+			"",
+			m.theme.TextBrand().Render("press enter to manage your subscriptions"),
+		))
+	}
+
+	order := m.order
+	lines := []string{
+		accent("Thank you for ordering with Terminal Products, Inc."),
+		"",
+		m.theme.TextBrand().Render(fmt.Sprintf("order #%d", order.Index)),
+		"",
+	}
+
+	for _, item := range order.Items {
+		lines = append(lines, base(m.formatOrderItem(item)+"  "+formatUSD(int(item.Amount))))
+	}
+	totals := api.OrderTotals(*order)
+	lines = append(lines,
+		"",
+		base("subtotal: "+formatUSD(int(totals.Subtotal))),
+		base("shipping: "+formatUSD(int(totals.Shipping))),
 	)
+	if totals.Discount > 0 {
+		lines = append(lines, base("discount: -"+formatUSD(int(totals.Discount))))
+	}
+	if tax := api.OrderTax(*order); tax != nil {
+		lines = append(lines, base(fmt.Sprintf("%-9s %s", tax.Label()+":", formatUSD(int(tax.Amount)))))
+	}
+	if totals.GiftCard > 0 {
+		lines = append(lines,
+			base("total:    "+formatUSD(int(totals.Total))),
+			base("gift card: -"+formatUSD(int(totals.GiftCard))),
+			accent("due:      "+formatUSD(int(totals.Due))),
+		)
+	} else {
+		lines = append(lines, accent("total:    "+formatUSD(int(totals.Total))))
+	}
+	lines = append(lines,
+		"",
+		accent("shipping to"),
+	)
+
+	address := order.Shipping
+	lines = append(lines, base(address.Name), base(address.Street1))
+	if address.Street2 != "" {
+		lines = append(lines, base(address.Street2))
+	}
+	lines = append(lines, base(strings.Join([]string{address.City, address.Province, address.Country + " " + address.Zip}, ", ")))
+
+	if m.state.final.service != "" || m.state.final.timeframe != "" {
+		lines = append(lines, "", accent("estimated delivery"))
+		if m.state.final.service != "" {
+			lines = append(lines, base(m.state.final.service))
+		}
+		if m.state.final.timeframe != "" {
+			lines = append(lines, base(m.state.final.timeframe))
+		}
+	}
+
+	url := orderURL(order)
+	if url == "" {
+		lines = append(lines, "", base("a confirmation is on its way to your inbox."))
+	} else {
+		lines = append(lines,
+			"",
+			base("a confirmation is on its way to your inbox. follow your order at"),
+			m.theme.TextBrand().Render(url),
+		)
+	}
+	details := lipgloss.JoinVertical(lipgloss.Left, lines...)
+
+	if url == "" {
+		return m.theme.Base().Width(m.widthContent).Padding(0, 1).Render(details)
+	}
+	qr, _, err := qrfefe.Generate(0, url)
+	if err != nil || m.size < large {
+		return m.theme.Base().Width(m.widthContent).Padding(0, 1).Render(details)
+	}
+	return m.theme.Base().Width(m.widthContent).Padding(0, 1).Render(lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		details,
+		"    ",
+		qr,
+	))
 }
@@ -1,11 +1,13 @@
 package tui
 
 import (
+	"context"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type footerState struct {
@@ -60,18 +62,18 @@ func (m model) ToggleRegion() (model, tea.Cmd) {
 	m.client = m.CreateSDKClient()
 
 	// Return command to reload data
-	cmd := func() tea.Msg {
-		_, err := m.client.Cart.Clear(m.context)
+	cmd := m.traced("View.Init", func(ctx context.Context) tea.Msg {
+		_, err := m.client.Cart.Clear(ctx)
 		if err != nil {
 			return err
 		}
 
-		response, err := m.client.View.Init(m.context)
+		response, err := m.client.View.Init(ctx, api.WithoutOrders())
 		if err != nil {
 			return err
 		}
 		return response.Data
-	}
+	})
 
 	return m, cmd
 }
@@ -144,6 +146,8 @@ func (m model) FooterView() string {
 			m.theme.PanelError().Width(space).Height(height).Render(),
 			m.theme.PanelError().Bold(true).Padding(0, 1).Height(height).Render(hint),
 		)
+	} else if m.state.retrying != nil {
+		content = m.theme.TextAccent().Render(m.RetryingView())
 	} else {
 		content = "free shipping on US orders over $40"
 	}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+// giftState is the gift form on the shipping page. Whether the cart is a gift
+// is kept by the API on the cart itself.
+type giftState struct {
+	editing bool
+	saving  bool
+	input   giftInput
+	form    *huh.Form
+}
+
+type giftInput struct {
+	enabled    bool
+	message    string
+	hidePrices bool
+}
+
+type GiftSavedMsg struct {
+	cart terminal.Cart
+}
+
+func (m model) Gift() *api.Gift {
+	if m.IsSubscribing() {
+		return nil
+	}
+	return api.CartGift(m.cart)
+}
+
+func (m model) GiftSwitch() (model, tea.Cmd) {
+	m.state.gift.editing = true
+	m.state.gift.input = giftInput{enabled: true}
+	if gift := m.Gift(); gift != nil {
+		m.state.gift.input = giftInput{enabled: true, message: gift.Message, hidePrices: gift.HidePrices}
+	}
+	m.state.gift.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("send as a gift?").
+				Key("enabled").
+				Value(&m.state.gift.input.enabled),
+			huh.NewText().
+				Title("gift message").
+				Key("message").
+				CharLimit(240).
+				Value(&m.state.gift.input.message).
+				Validate(validate.WithinLen(0, 240, "gift message")),
+			huh.NewConfirm().
+				Title("leave prices off the packing slip?").
+				Key("hidePrices").
+				Value(&m.state.gift.input.hidePrices),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithWidth(m.widthContent).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "next"},
+	}
+	return m, m.state.gift.form.Init()
+}
+
+func (m model) GiftUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.state.gift.editing = false
+			return m.ShippingSwitch()
+		}
+	}
+
+	next, cmd := m.state.gift.form.Update(msg)
+	m.state.gift.form = next.(*huh.Form)
+	if !m.state.gift.saving && m.state.gift.form.State == huh.StateCompleted {
+		m.state.gift.saving = true
+		form := m.state.gift.form
+		input := giftInput{
+			enabled:    form.GetBool("enabled"),
+			message:    form.GetString("message"),
+			hidePrices: form.GetBool("hidePrices"),
+		}
+		m.state.gift.input = input
+		return m, m.traced("Cart.SetGift", func(ctx context.Context) tea.Msg {
+			var err error
+			if input.enabled {
+				err = api.SetCartGift(ctx, m.client, api.Gift{Message: input.message, HidePrices: input.hidePrices})
+			} else {
+				err = api.RemoveCartGift(ctx, m.client)
+			}
+			if err != nil {
+				return err
+			}
+			cart, err := m.client.Cart.Get(ctx)
+			if err != nil {
+				return err
+			}
+			return GiftSavedMsg{cart: cart.Data}
+		})
+	}
+	return m, cmd
+}
+
+func (m model) GiftView() string {
+	if m.state.gift.saving {
+		return m.theme.Base().Width(m.widthContent).Render(" saving gift options...")
+	}
+	return m.state.gift.form.View()
+}
+
+// GiftSummaryView describes the gift options in a line or two, for the
+// shipping and confirm pages.
+func (m model) GiftSummaryView(gift *api.Gift, width int) string {
+	text := "this order is a gift"
+	if gift.HidePrices {
+		text += ", without prices on the packing slip"
+	}
+	if gift.Message != "" {
+		text += ": \"" + gift.Message + "\""
+	}
+	return m.theme.TextBrand().Render(wordWrap(text, width))
+}
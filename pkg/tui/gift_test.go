@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestGiftForm(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		method string
+		want   api.Gift
+	}{
+		{
+			name:   "gift",
+			keys:   []string{"enter", "happy birthday", "enter", "left", "enter"},
+			method: "PUT",
+			want:   api.Gift{Message: "happy birthday", HidePrices: true},
+		},
+		{
+			name:   "not a gift",
+			keys:   []string{"left", "enter", "enter", "enter"},
+			method: "DELETE",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tm := newTestModel(t, nil)
+			tm.m = tm.m.SwitchPage(shippingPage)
+			tm.switchTo(tm.m.GiftSwitch())
+			tm.press(test.keys...)
+
+			request, ok := tm.api.request(test.method, "/cart/gift")
+			if !ok {
+				t.Fatalf("no %s of the cart's gift", test.method)
+			}
+			if test.method == "DELETE" {
+				return
+			}
+			gift := api.Gift{}
+			if err := json.Unmarshal([]byte(request.body), &gift); err != nil {
+				t.Fatal(err)
+			}
+			if gift != test.want {
+				t.Errorf("got %+v, want %+v", gift, test.want)
+			}
+		})
+	}
+}
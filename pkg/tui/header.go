@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 func (m model) HeaderUpdate(msg tea.Msg) (model, tea.Cmd) {
@@ -33,7 +34,9 @@ func (m model) HeaderUpdate(msg tea.Msg) (model, tea.Cmd) {
 }
 
 func (m model) HeaderView() string {
-	total := m.cart.Subtotal
+	// the cart's own subtotal keeps up with quantity changes before the API
+	// has confirmed them, shipping and tax aren't known yet
+	total := api.NewTotals(m.cart.Subtotal, 0, 0, m.codes).Total
 	count := int64(0)
 	if m.cart.Items != nil {
 		for _, item := range m.cart.Items {
@@ -117,7 +120,7 @@ func (m model) HeaderView() string {
 		}
 	}
 
-	return table.New().
+	header := table.New().
 		Border(lipgloss.NormalBorder()).
 		BorderStyle(m.renderer.NewStyle().Foreground(m.theme.Border())).
 		Row(tabs...).
@@ -128,4 +131,9 @@ func (m model) HeaderView() string {
 				AlignHorizontal(lipgloss.Center)
 		}).
 		Render()
+
+	if banner := m.BannerView(); banner != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, header, banner)
+	}
+	return header
 }
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+type KeyLinkCodeMsg struct {
+	code api.KeyLinkCode
+}
+
+type keysState struct {
+	selected   int
+	deleting   *int
+	removing   string
+	generating bool
+	code       *api.KeyLinkCode
+}
+
+func (m model) LoadKeysCmd() tea.Cmd {
+	return m.traced("Key.List", func(ctx context.Context) tea.Msg {
+		keys, err := api.ListKeys(ctx, m.client)
+		if err != nil {
+			return err
+		}
+		return keys
+	})
+}
+
+func (m model) nextKey() (model, tea.Cmd) {
+	next := m.state.keys.selected + 1
+	max := len(m.keys)
+	if next > max {
+		next = max
+	}
+
+	m.state.keys.selected = next
+	return m, nil
+}
+
+func (m model) previousKey() (model, tea.Cmd) {
+	next := m.state.keys.selected - 1
+	if next < 0 {
+		next = 0
+	}
+
+	m.state.keys.selected = next
+	return m, nil
+}
+
+func (m model) KeysUpdate(msg tea.Msg) (model, tea.Cmd) {
+	m.state.footer.commands = []footerCommand{
+		{key: "↑/↓", value: "navigate"},
+		{key: "x/del", value: "remove"},
+		{key: "esc", value: "back"},
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down", "tab":
+			if m.state.keys.deleting == nil {
+				return m.nextKey()
+			}
+		case "k", "up", "shift+tab":
+			if m.state.keys.deleting == nil {
+				return m.previousKey()
+			}
+		case "delete", "d", "backspace", "x":
+			if m.state.keys.deleting == nil && m.state.keys.removing == "" && m.state.keys.selected < len(m.keys) {
+				m.state.keys.deleting = &m.state.keys.selected
+			}
+			return m, nil
+		case "y":
+			if m.state.keys.deleting != nil {
+				m.state.keys.deleting = nil
+				key := m.keys[m.state.keys.selected]
+				if key.Fingerprint == m.fingerprint {
+					return m, func() tea.Msg {
+						return VisibleError{message: "you can't remove the key you're connected with"}
+					}
+				}
+				m.state.keys.removing = key.ID
+				return m, m.traced("Key.Delete", func(ctx context.Context) tea.Msg {
+					err := api.DeleteKey(ctx, m.client, key.ID)
+					if err != nil {
+						return err
+					}
+					keys, err := api.ListKeys(ctx, m.client)
+					if err != nil {
+						return err
+					}
+					return keys
+				})
+			}
+			return m, nil
+		case "n", "esc":
+			m.state.keys.deleting = nil
+			return m, nil
+		case "enter":
+			if m.state.keys.deleting == nil && m.state.keys.selected == len(m.keys) {
+				m.state.keys.generating = true
+				return m, m.traced("Key.LinkCode", func(ctx context.Context) tea.Msg {
+					code, err := api.NewKeyLinkCode(ctx, m.client)
+					if err != nil {
+						return err
+					}
+					return KeyLinkCodeMsg{code: *code}
+				})
+			}
+		}
+	case KeyLinkCodeMsg:
+		m.state.keys.generating = false
+		m.state.keys.code = &msg.code
+	case error:
+		m.state.keys.generating = false
+	}
+
+	return m, nil
+}
+
+func (m model) formatKey(key api.Key) string {
+	lines := []string{}
+	fingerprint := m.theme.TextAccent().Render(key.Fingerprint)
+	if key.Fingerprint == m.fingerprint {
+		fingerprint += m.theme.Base().Render(" (this key)")
+	}
+	lines = append(lines, fingerprint)
+	lines = append(lines, "linked: "+key.Created)
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m model) formatKeyLinkCode(totalWidth int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	if m.state.keys.generating {
+		return base("generating code...")
+	}
+
+	code := m.state.keys.code
+	lines := []string{}
+	lines = append(lines, base(wordWrap("from the machine with the key you want to link, run:", totalWidth-4)))
+	lines = append(lines, "")
+	lines = append(lines, m.theme.TextBrand().Bold(true).Render("ssh terminal.shop link "+code.Code))
+	lines = append(lines, "")
+	lines = append(lines, accent("expires: ")+base(code.Expires))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m model) KeysView(totalWidth int, focused bool) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	keys := []string{}
+	for i, key := range m.keys {
+		content := m.formatKey(key)
+		if m.state.keys.deleting != nil && *m.state.keys.deleting == i {
+			content = accent("are you sure you want to remove?") + base("\n(y/n)")
+		}
+		if key.ID == m.state.keys.removing {
+			content = base("removing...")
+		}
+		box := m.CreateBoxCustom(
+			content,
+			focused && i == m.state.keys.selected,
+			totalWidth,
+		)
+		keys = append(keys, box)
+	}
+
+	newKeyIndex := len(m.keys)
+	newKeyContent := m.formatListItemCustom("link another ssh key", m.state.keys.selected == newKeyIndex, totalWidth, false)
+	if m.state.keys.generating || m.state.keys.code != nil {
+		newKeyContent = m.formatKeyLinkCode(totalWidth)
+	}
+	newKey := m.CreateBoxCustom(
+		newKeyContent,
+		focused && m.state.keys.selected == newKeyIndex,
+		totalWidth,
+	)
+	keys = append(keys, newKey)
+
+	return m.theme.Base().Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		keys...,
+	))
+}
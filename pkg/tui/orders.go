@@ -1,28 +1,112 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
 )
 
 type ordersState struct {
 	selected int
 	viewing  bool // When true, we're viewing a single order in detail
+	receipt  bool // When true, the order is shown as a receipt to copy
 	yOffset  int
+	// detail is the order being viewed, which needn't be in list when it
+	// was just placed.
+	detail *terminal.Order
+	// list holds the orders loaded so far for query, a page at a time, and
+	// next is the cursor of the page after them.
+	list      []terminal.Order
+	next      string
+	loading   bool
+	loaded    bool
+	query     api.OrderQuery
+	filtering bool
+	filter    orderFilter
+	form      *huh.Form
+	// cancelling asks to confirm cancelling the order being viewed, and
+	// updating waits for the API to cancel it or take a request about it.
+	cancelling  bool
+	updating    bool
+	requesting  bool
+	request     orderRequestInput
+	requestForm *huh.Form
 }
 
-func (m model) nextOrder() (model, tea.Cmd) {
-	next := m.state.orders.selected + 1
-	max := len(m.orders) - 1
-	if next > max {
-		next = max
+// ordersPageSize is how many orders are loaded at a time.
+const ordersPageSize = 20
+
+type OrdersPageMsg struct {
+	query api.OrderQuery
+	page  api.OrderPage
+}
+
+// orderSorts are the orders the list cycles through with s.
+var orderSorts = []struct {
+	sort      string
+	ascending bool
+	name      string
+}{
+	{api.SortByDate, false, "newest first"},
+	{api.SortByDate, true, "oldest first"},
+	{api.SortByAmount, false, "highest total first"},
+	{api.SortByAmount, true, "lowest total first"},
+}
+
+// loadOrders loads the page of orders for the current query from cursor,
+// the first page when it's empty.
+func (m model) loadOrders(cursor string) (model, tea.Cmd) {
+	query := m.state.orders.query
+	query.Cursor = cursor
+	query.Limit = ordersPageSize
+	m.state.orders.loading = true
+	return m, m.traced("Order.List", func(ctx context.Context) tea.Msg {
+		page, err := api.ListOrders(ctx, m.client, query)
+		if err != nil {
+			return err
+		}
+		return OrdersPageMsg{query: query, page: page}
+	})
+}
+
+// ordersLoaded adds a page to the list, unless the query changed while it
+// was loading.
+func (m model) ordersLoaded(msg OrdersPageMsg) model {
+	query := msg.query
+	query.Cursor = ""
+	query.Limit = 0
+	if query != m.state.orders.query {
+		return m
+	}
+	m.state.orders.loading = false
+	m.state.orders.loaded = true
+	m.state.orders.next = msg.page.Next
+	if msg.query.Cursor == "" {
+		m.state.orders.list = msg.page.Orders
+		m.state.orders.selected = 0
+	} else {
+		m.state.orders.list = append(m.state.orders.list, msg.page.Orders...)
 	}
+	return m
+}
 
+func (m model) nextOrder() (model, tea.Cmd) {
+	next := min(m.state.orders.selected+1, max(len(m.state.orders.list)-1, 0))
 	m.state.orders.selected = next
+
+	// load the next page as the last loaded order comes up
+	if next >= len(m.state.orders.list)-1 && m.state.orders.next != "" && !m.state.orders.loading {
+		return m.loadOrders(m.state.orders.next)
+	}
 	return m, nil
 }
 
@@ -32,25 +116,114 @@ func (m model) previousOrder() (model, tea.Cmd) {
 	return m, nil
 }
 
+func (m model) sortOrders() (model, tea.Cmd) {
+	current := 0
+	for i, sort := range orderSorts {
+		if sort.sort == m.state.orders.query.Sort && sort.ascending == m.state.orders.query.Ascending {
+			current = i
+		}
+	}
+	sort := orderSorts[(current+1)%len(orderSorts)]
+	m.state.orders.query.Sort = sort.sort
+	m.state.orders.query.Ascending = sort.ascending
+	return m.loadOrders("")
+}
+
 var orderCommands = []footerCommand{
 	{key: "↑/↓", value: "navigate"},
 	{key: "enter", value: "view details"},
+	{key: "s", value: "sort"},
+	{key: "f", value: "filter"},
 	{key: "esc", value: "back"},
 }
 
+// orderDetailCommands are the footer of an order's detail, with what can
+// still be done about it.
+func orderDetailCommands(order terminal.Order) []footerCommand {
+	commands := []footerCommand{
+		{key: "esc", value: "back to orders"},
+		{key: "r", value: "receipt"},
+	}
+	if api.CanCancel(order) {
+		commands = append(commands, footerCommand{key: "x", value: "cancel order"})
+	}
+	if api.CanRequest(order) {
+		commands = append(commands, footerCommand{key: "t", value: "return or report a problem"})
+	}
+	return commands
+}
+
+var receiptCommands = []footerCommand{
+	{key: "esc", value: "back to order"},
+	{key: "c", value: "copy"},
+}
+
+// OrderDetailSwitch opens order in the account page.
+func (m model) OrderDetailSwitch(order terminal.Order) (model, tea.Cmd) {
+	m, cmd := m.AccountPageSwitch(ordersPage)
+	m.state.orders.viewing = true
+	m.state.orders.receipt = false
+	m.state.orders.filtering = false
+	m.state.orders.detail = &order
+	m.state.orders.cancelling = false
+	m.state.footer.commands = orderDetailCommands(order)
+	return m, cmd
+}
+
 func (m model) OrdersUpdate(msg tea.Msg) (model, tea.Cmd) {
-	if len(m.state.footer.commands) != 3 && !m.state.orders.viewing {
+	if m.state.orders.filtering {
+		return m.OrderFilterUpdate(msg)
+	}
+	if m.state.orders.requesting {
+		return m.OrderRequestUpdate(msg)
+	}
+	if !m.state.orders.viewing {
 		m.state.footer.commands = orderCommands
 	}
 
 	if m.state.orders.viewing {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if m.state.orders.updating {
+				return m, nil
+			}
+			if m.state.orders.cancelling {
+				switch msg.String() {
+				case "y":
+					return m.cancelOrder()
+				case "n", "esc":
+					m.state.orders.cancelling = false
+				}
+				return m, nil
+			}
 			switch msg.String() {
 			case "esc", "q", "backspace":
+				if m.state.orders.receipt {
+					m.state.orders.receipt = false
+					m.state.footer.commands = orderDetailCommands(*m.state.orders.detail)
+					return m, nil
+				}
 				m.state.footer.commands = orderCommands
 				m.state.orders.viewing = false
 				return m, nil
+			case "r":
+				m.state.orders.receipt = true
+				m.state.account.detailViewport.GotoTop()
+				m.state.footer.commands = receiptCommands
+				return m, nil
+			case "c":
+				if m.state.orders.receipt {
+					return m, m.copyReceipt()
+				}
+			case "x":
+				if !m.state.orders.receipt && api.CanCancel(*m.state.orders.detail) {
+					m.state.orders.cancelling = true
+					return m, nil
+				}
+			case "t":
+				if !m.state.orders.receipt && api.CanRequest(*m.state.orders.detail) {
+					return m.OrderRequestSwitch()
+				}
 			}
 			var cmd tea.Cmd
 			m.state.account.detailViewport.KeyMap = viewport.DefaultKeyMap()
@@ -65,13 +238,22 @@ func (m model) OrdersUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.nextOrder()
 			case "k", "up", "shift+tab":
 				return m.previousOrder()
+			case "s":
+				return m.sortOrders()
+			case "f":
+				return m.OrderFilterSwitch()
 			case "enter":
+				if m.state.orders.selected >= len(m.state.orders.list) {
+					return m, nil
+				}
+				order := m.state.orders.list[m.state.orders.selected]
 				m.state.orders.viewing = true
+				m.state.orders.receipt = false
+				m.state.orders.detail = &order
+				m.state.orders.cancelling = false
 				m.state.orders.yOffset = m.state.account.detailViewport.YOffset
 				m.state.account.detailViewport.GotoTop()
-				m.state.footer.commands = []footerCommand{
-					{key: "esc", value: "back to orders"},
-				}
+				m.state.footer.commands = orderDetailCommands(order)
 				return m, nil
 			}
 		}
@@ -99,25 +281,32 @@ func (m model) formatOrderItem(orderItem terminal.OrderItem) string {
 	return fmt.Sprintf("%dx %s", orderItem.Quantity, product.Name)
 }
 
-func (m model) formatOrder(order terminal.Order, index int) string {
-	orderNumber := fmt.Sprintf("order #%d", index)
-	price := fmt.Sprintf("  $%2v", (order.Amount.Subtotal+order.Amount.Shipping)/100)
+func (m model) formatOrder(order terminal.Order) string {
+	orderNumber := fmt.Sprintf("order #%d", order.Index)
+	price := "  " + formatUSD(int(orderTotal(order)))
+
+	label := "  " + api.OrderStatus(order)
+	if api.OrderGift(order) != nil {
+		label += m.theme.TextBrand().Render("  gift")
+	}
 
 	content := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		m.theme.TextAccent().Render(orderNumber),
 		m.theme.Base().Render(price),
+		m.theme.Base().Render(label),
 	)
 
 	// Show only order date instead of individual items
 	lines := []string{}
 	lines = append(lines, content)
-	lines = append(lines, fmt.Sprintf("date: %s", order.Created))
+	lines = append(lines, fmt.Sprintf("date: %s", m.formatDate(order.Created)))
+	lines = append(lines, fmt.Sprintf("id: %s", order.ID))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
-func (m model) formatOrderDetail(order terminal.Order, index int) string {
+func (m model) formatOrderDetail(order terminal.Order) string {
 	base := m.theme.Base().Render
 	accent := m.theme.TextAccent().Render
 	highlight := m.theme.TextBrand().Render
@@ -126,11 +315,54 @@ func (m model) formatOrderDetail(order terminal.Order, index int) string {
 	lines = append(lines, base("< ")+accent("esc ")+base("back to orders\n"))
 
 	// Order header
-	orderNumber := fmt.Sprintf("order #%d", index)
+	orderNumber := fmt.Sprintf("order #%d", order.Index)
 	lines = append(lines, highlight(orderNumber))
-	lines = append(lines, base("date: ")+base(order.Created))
+	lines = append(lines, base("id: ")+base(order.ID))
+	lines = append(lines, base("date: ")+base(m.formatDate(order.Created)))
+	if api.OrderStatus(order) == api.OrderCancelled {
+		lines = append(lines, m.theme.TextError().Render("cancelled and refunded"))
+	}
 	lines = append(lines, "")
 
+	if m.state.orders.updating {
+		lines = append(lines, accent("updating order..."), "")
+	} else if m.state.orders.cancelling {
+		lines = append(lines, accent("cancel this order?")+base(" (y/n)"), "")
+	}
+
+	// Returns and problem reports
+	if requests := m.orderRequestLines(order); len(requests) > 0 {
+		lines = append(lines, accent("requests"))
+		lines = append(lines, requests...)
+		lines = append(lines, "")
+	}
+
+	// Gift details
+	if gift := api.OrderGift(order); gift != nil {
+		lines = append(lines, accent("gift"))
+		lines = append(lines, base("to: ")+base(order.Shipping.Name))
+		if gift.Message != "" {
+			lines = append(lines, base("message: ")+base(gift.Message))
+		}
+		if gift.HidePrices {
+			lines = append(lines, base("prices left off the packing slip"))
+		}
+		lines = append(lines, "")
+	}
+
+	// Notes left at checkout
+	instructions, note := api.OrderInstructions(order), api.OrderNote(order)
+	if instructions != "" || note != "" {
+		lines = append(lines, accent("notes"))
+		if instructions != "" {
+			lines = append(lines, base("delivery: ")+base(instructions))
+		}
+		if note != "" {
+			lines = append(lines, base("note: ")+base(note))
+		}
+		lines = append(lines, "")
+	}
+
 	// Shipping details
 	if order.Tracking.Service != "" || order.Tracking.Number != "" {
 		lines = append(lines, accent("shipping"))
@@ -154,28 +386,65 @@ func (m model) formatOrderDetail(order terminal.Order, index int) string {
 
 	// Order totals
 	lines = append(lines, accent("totals"))
-	lines = append(lines, base("subtotal: ")+base(fmt.Sprintf("$%d", order.Amount.Subtotal/100)))
-	lines = append(lines, base("shipping: ")+base(fmt.Sprintf("$%d", order.Amount.Shipping/100)))
-	lines = append(lines, base("total: ")+base(fmt.Sprintf("$%d", (order.Amount.Subtotal+order.Amount.Shipping)/100)))
+	totals := api.OrderTotals(order)
+	lines = append(lines, base("subtotal: ")+base(formatUSD(int(totals.Subtotal))))
+	lines = append(lines, base("shipping: ")+base(formatUSD(int(totals.Shipping))))
+	if totals.Discount > 0 {
+		lines = append(lines, base("discount: ")+base("-"+formatUSD(int(totals.Discount))))
+	}
+	tax := api.OrderTax(order)
+	if tax != nil {
+		lines = append(lines, base(tax.Label()+": ")+base(formatUSD(int(tax.Amount))))
+	}
+	lines = append(lines, base("total: ")+base(formatUSD(int(totals.Total))))
+	if totals.GiftCard > 0 {
+		lines = append(lines, base("gift card: ")+base("-"+formatUSD(int(totals.GiftCard))))
+		lines = append(lines, base("due: ")+base(formatUSD(int(totals.Due))))
+	}
+	if tax != nil && tax.VATID != "" {
+		lines = append(lines, "")
+		lines = append(lines, accent("invoice"))
+		for _, line := range formatInvoice(*tax, totals.Total-totals.Tax) {
+			lines = append(lines, base(line))
+		}
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+// orderTotal is what an order cost, with its discount and tax, including
+// what gift cards paid.
+func orderTotal(order terminal.Order) int64 {
+	return api.OrderTotals(order).Total
+}
+
 func (m model) OrdersView(totalWidth int, focused bool) string {
 	base := m.theme.Base().Render
 
+	// If we're viewing a single order as a receipt
+	if m.state.orders.viewing && m.state.orders.receipt && m.state.orders.detail != nil {
+		return m.theme.Base().Width(totalWidth).Render(m.ReceiptView(*m.state.orders.detail))
+	}
+
+	if m.state.orders.viewing && m.state.orders.requesting {
+		return m.OrderRequestView(totalWidth)
+	}
+
 	// If we're viewing a single order in detail
-	if m.state.orders.viewing && len(m.orders) > 0 {
-		orderIndex := len(m.orders) - m.state.orders.selected - 1
-		detailContent := m.formatOrderDetail(m.orders[m.state.orders.selected], orderIndex)
+	if m.state.orders.viewing && m.state.orders.detail != nil {
+		detailContent := m.formatOrderDetail(*m.state.orders.detail)
 		return m.theme.Base().Width(totalWidth).Render(detailContent)
 	}
 
+	if m.state.orders.filtering {
+		return m.OrderFilterView(totalWidth)
+	}
+
 	// Otherwise show order list
 	orders := []string{}
-	for i, order := range m.orders {
+	for i, order := range m.state.orders.list {
 		content := m.formatListItemCustom(
-			m.formatOrder(order, len(m.orders)-i-1),
+			m.formatOrder(order),
 			focused && i == m.state.orders.selected,
 			totalWidth,
 			false,
@@ -188,19 +457,172 @@ func (m model) OrdersView(totalWidth int, focused bool) string {
 		orders = append(orders, box)
 	}
 
+	if m.state.orders.loading {
+		orders = append(orders, base(" loading orders..."))
+	} else if m.state.orders.next != "" {
+		orders = append(orders, base(" more below"))
+	}
+
 	orderList := lipgloss.JoinVertical(lipgloss.Left, orders...)
 	if len(orders) == 0 {
+		empty := "no orders found"
+		if !m.state.orders.loaded {
+			empty = "couldn't load your orders, open the account again to retry"
+		} else if m.state.orders.query != (api.OrderQuery{}) {
+			empty = "no orders match, press f to change the filter"
+		}
 		return lipgloss.Place(
 			totalWidth,
 			m.heightContent,
 			lipgloss.Center,
 			lipgloss.Center,
-			base("no orders found"),
+			base(empty),
 		)
 	}
 
 	return m.theme.Base().Render(lipgloss.JoinVertical(
 		lipgloss.Left,
+		" "+m.orderQueryView(),
 		orderList,
 	))
 }
+
+// orderFilter is the filter form's input, empty fields filtering nothing.
+type orderFilter struct {
+	status    string
+	variantID string
+	from      string
+	to        string
+}
+
+func (m model) OrderFilterSwitch() (model, tea.Cmd) {
+	m.state.orders.filtering = true
+	query := m.state.orders.query
+	m.state.orders.filter = orderFilter{status: query.Status, variantID: query.ProductVariantID}
+	if !query.From.IsZero() {
+		m.state.orders.filter.from = query.From.Format(time.DateOnly)
+	}
+	if !query.Before.IsZero() {
+		m.state.orders.filter.to = lastDay(query).Format(time.DateOnly)
+	}
+
+	products := []huh.Option[string]{huh.NewOption("any product", "")}
+	for _, product := range m.products {
+		for _, variant := range product.Variants {
+			name := product.Name
+			if len(product.Variants) > 1 {
+				name += " (" + strings.ToLower(variant.Name) + ")"
+			}
+			products = append(products, huh.NewOption(name, variant.ID))
+		}
+	}
+
+	m.state.orders.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("status").
+				Key("status").
+				Options(
+					huh.NewOption("any status", ""),
+					huh.NewOption(api.OrderPending, api.OrderPending),
+					huh.NewOption(api.OrderShipped, api.OrderShipped),
+					huh.NewOption(api.OrderDelivered, api.OrderDelivered),
+					huh.NewOption(api.OrderCancelled, api.OrderCancelled),
+				).
+				Value(&m.state.orders.filter.status),
+			huh.NewSelect[string]().
+				Title("product").
+				Key("product").
+				Options(products...).
+				Value(&m.state.orders.filter.variantID),
+			huh.NewInput().
+				Title("from (yyyy-mm-dd)").
+				Key("from").
+				Value(&m.state.orders.filter.from).
+				Validate(validate.IsDate("from")),
+			huh.NewInput().
+				Title("to (yyyy-mm-dd)").
+				Key("to").
+				Value(&m.state.orders.filter.to).
+				Validate(validate.IsDate("to")),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "next"},
+	}
+	return m, m.state.orders.form.Init()
+}
+
+func (m model) OrderFilterUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.state.orders.filtering = false
+			m.state.footer.commands = orderCommands
+			return m, nil
+		}
+	}
+
+	next, cmd := m.state.orders.form.Update(msg)
+	m.state.orders.form = next.(*huh.Form)
+	if m.state.orders.form.State == huh.StateCompleted {
+		form := m.state.orders.form
+		filter := orderFilter{
+			status:    form.GetString("status"),
+			variantID: form.GetString("product"),
+			from:      form.GetString("from"),
+			to:        form.GetString("to"),
+		}
+		m.state.orders.filter = filter
+		m.state.orders.filtering = false
+		m.state.footer.commands = orderCommands
+		m.state.orders.query.Status = filter.status
+		m.state.orders.query.ProductVariantID = filter.variantID
+		// the dates are days in the customer's timezone, up to the end of the
+		// last one
+		m.state.orders.query.From, _ = time.ParseInLocation(time.DateOnly, filter.from, m.location)
+		m.state.orders.query.Before = time.Time{}
+		if to, err := time.ParseInLocation(time.DateOnly, filter.to, m.location); err == nil {
+			m.state.orders.query.Before = to.AddDate(0, 0, 1)
+		}
+		return m.loadOrders("")
+	}
+	return m, cmd
+}
+
+func (m model) OrderFilterView(totalWidth int) string {
+	return m.state.orders.form.WithWidth(totalWidth).View()
+}
+
+// orderQueryView describes how the list is sorted and filtered.
+func (m model) orderQueryView() string {
+	query := m.state.orders.query
+	parts := []string{orderSorts[0].name}
+	for _, sort := range orderSorts {
+		if sort.sort == query.Sort && sort.ascending == query.Ascending {
+			parts[0] = sort.name
+		}
+	}
+	if query.Status != "" {
+		parts = append(parts, query.Status)
+	}
+	if query.ProductVariantID != "" {
+		parts = append(parts, m.productName(query.ProductVariantID))
+	}
+	if !query.From.IsZero() {
+		parts = append(parts, "from "+query.From.Format(time.DateOnly))
+	}
+	if !query.Before.IsZero() {
+		parts = append(parts, "to "+lastDay(query).Format(time.DateOnly))
+	}
+	return m.theme.Base().Render(strings.Join(parts, " · "))
+}
+
+// lastDay is the last day the query keeps orders from, the one before its
+// exclusive bound.
+func lastDay(query api.OrderQuery) time.Time {
+	return query.Before.AddDate(0, 0, -1)
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+func TestPlacedOrderPagesInLazily(t *testing.T) {
+	tm := newTestModel(t, map[string]string{
+		"GET /order?limit=20": `{"data": [{"id": "ord_2", "index": 2}, {"id": "ord_1", "index": 1}]}`,
+	})
+	tm.switchTo(tm.m.AccountSwitch())
+	if !tm.m.state.orders.loaded || len(tm.m.state.orders.list) != 2 {
+		t.Fatalf("got %d orders", len(tm.m.state.orders.list))
+	}
+
+	tm.m.order = &terminal.Order{ID: "ord_3", Index: 3}
+	tm.switchTo(tm.m.FinalSwitch())
+	if got := tm.api.count("GET", "/order"); got != 1 {
+		t.Fatalf("placing an order loaded the orders again, %d loads", got)
+	}
+
+	tm.press("enter")
+	if got := tm.api.count("GET", "/order"); got != 2 {
+		t.Errorf("got %d loads, want the first page again once the order is viewed", got)
+	}
+	if detail := tm.m.state.orders.detail; detail == nil || detail.ID != "ord_3" {
+		t.Errorf("got detail %+v, want the placed order", detail)
+	}
+}
+
+func TestOrderFilter(t *testing.T) {
+	tm := newTestModel(t, nil)
+	tm.m.location = time.FixedZone("CEST", 2*60*60)
+	tm.switchTo(tm.m.AccountPageSwitch(ordersPage))
+
+	tm.press("f")
+	if !tm.m.state.orders.filtering {
+		t.Fatal("the filter form didn't open")
+	}
+	// delivered orders of any product placed in september
+	tm.press("down", "down", "down", "enter", "enter", "2026-09-01", "enter", "2026-09-30")
+	if tm.m.page != accountPage || tm.quit {
+		t.Fatalf("typing the filter left the form for page %d", tm.m.page)
+	}
+	tm.press("enter")
+
+	request, ok := tm.api.request("GET", "/order")
+	if !ok {
+		t.Fatal("no orders loaded")
+	}
+	query, err := url.ParseQuery(request.query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"status": {"delivered"},
+		"from":   {"2026-09-01T00:00:00+02:00"},
+		"before": {"2026-10-01T00:00:00+02:00"},
+		"limit":  {"20"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Errorf("got query %s, want %s", query.Encode(), want.Encode())
+	}
+}
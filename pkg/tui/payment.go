@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/stripe/stripe-go/v78"
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
 	"github.com/terminaldotshop/terminal/go/pkg/tui/qrfefe"
 	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
 )
@@ -33,28 +35,41 @@ type paymentInput struct {
 type paymentState struct {
 	selected   int
 	deleting   *int
+	removing   string
 	view       paymentView
 	input      paymentInput
 	form       *huh.Form
 	submitting bool
 	generating bool
-	url        *string
+	collection *api.CardCollection
+	expired    bool
+	poll       int
 }
 
 type SelectedCardUpdatedMsg struct {
 	cardID string
 }
 
+type CardAddedMsg struct {
+	cardID string
+	cards  []terminal.Card
+}
+
+// PollPaymentInitMsg and PollPaymentStatusMsg carry the poll they belong to,
+// so one that's been abandoned, by leaving the page or starting over, stops
+// at its next tick.
 type PollPaymentInitMsg struct {
-	paymentUrl string
+	poll       int
+	collection *api.CardCollection
 }
 
 type PollPaymentStatusMsg struct {
-	cardCount int
+	poll int
 }
 
 type PollPaymentCompleteMsg struct {
-	cards []terminal.Card
+	cardID string
+	cards  []terminal.Card
 }
 
 func (m model) GetSelectedCard() *terminal.Card {
@@ -80,6 +95,7 @@ func (m model) PaymentSwitch() (model, tea.Cmd) {
 		return m, nil
 	}
 	m = m.SwitchPage(paymentPage)
+	m.state.payment.poll++
 	m.state.footer.commands = []footerCommand{
 		{key: "esc", value: "back"},
 		{key: "↑/↓", value: "cards"},
@@ -220,45 +236,80 @@ func (m model) previousPaymentMethod() (model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) SetCard(cardID string) error {
+// SetCard pays for the cart with cardID. Subscriptions take the card at
+// checkout, so there's no cart to set.
+func (m model) SetCard(ctx context.Context, cardID string) tea.Msg {
 	if m.IsSubscribing() {
-		return nil
+		return SelectedCardUpdatedMsg{cardID: cardID}
 	}
 
 	params := terminal.CartSetCardParams{CardID: terminal.F(cardID)}
-	_, err := m.client.Cart.SetCard(m.context, params)
-	return err
+	_, err := m.client.Cart.SetCard(ctx, params)
+	if err != nil {
+		return err
+	}
+	return SelectedCardUpdatedMsg{cardID: cardID}
 }
 
 func (m model) choosePaymentMethod() (model, tea.Cmd) {
 	if m.state.payment.selected < len(m.cards) { // existing method
 		cardID := m.cards[m.state.payment.selected].ID
 		m.state.payment.submitting = true
-		return m, func() tea.Msg {
-			err := m.SetCard(cardID)
-			if err != nil {
-				return err
-			}
-			return SelectedCardUpdatedMsg{cardID: cardID}
-		}
+		return m, m.traced("Cart.SetCard", func(ctx context.Context) tea.Msg {
+			return m.SetCard(ctx, cardID)
+		})
 	} else if m.state.payment.selected == len(m.cards) { // new ssh
 		m.state.payment.input = paymentInput{}
 		m.state.payment.view = paymentFormView
 	} else if m.state.payment.selected == len(m.cards)+1 { // new https
-		m.state.payment.generating = true
-		m.state.payment.view = paymentHttpsView
-		return m, func() tea.Msg {
-			resp, err := m.client.Card.Collect(m.context)
-			if err != nil {
-				return err
-			}
-			return PollPaymentInitMsg{paymentUrl: resp.Data.URL}
-		}
+		return m.startCardCollection()
 	}
 
 	return m, nil
 }
 
+// startCardCollection gets a new link to enter a card in the browser,
+// abandoning any earlier one.
+func (m model) startCardCollection() (model, tea.Cmd) {
+	m.state.payment.generating = true
+	m.state.payment.view = paymentHttpsView
+	m.state.payment.collection = nil
+	m.state.payment.expired = false
+	m.state.payment.poll++
+	poll := m.state.payment.poll
+	return m, m.traced("Card.Collect", func(ctx context.Context) tea.Msg {
+		collection, err := api.StartCardCollection(ctx, m.client)
+		if err != nil {
+			return err
+		}
+		return PollPaymentInitMsg{poll: poll, collection: collection}
+	})
+}
+
+// pollCardCollection checks for the collected card a second from now. A
+// failed check is only logged, the next one may well succeed and the
+// collection times out if they never do.
+func (m model) pollCardCollection(poll int) tea.Cmd {
+	collection := m.state.payment.collection
+	check := m.traced("Card.List", func(ctx context.Context) tea.Msg {
+		card, cards, err := collection.Check(ctx, m.client)
+		if err != nil {
+			logger.FromContext(ctx).Warn("card collection check failed", "error", err)
+			return PollPaymentStatusMsg{poll: poll}
+		}
+		if card != nil {
+			return PollPaymentCompleteMsg{cardID: card.ID, cards: cards}
+		}
+		return PollPaymentStatusMsg{poll: poll}
+	})
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		if m.context.Err() != nil {
+			return nil
+		}
+		return check()
+	})
+}
+
 func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 
@@ -281,27 +332,26 @@ func (m model) paymentListUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.previousPaymentMethod()
 			}
 		case "delete", "d", "backspace", "x":
-			if m.state.payment.deleting == nil && m.state.payment.selected < len(m.cards) {
+			if m.state.payment.deleting == nil && m.state.payment.removing == "" && m.state.payment.selected < len(m.cards) {
 				m.state.payment.deleting = &m.state.payment.selected
 			}
 			return m, nil
 		case "y":
 			if m.state.payment.deleting != nil {
 				m.state.payment.deleting = nil
-				_, err := m.client.Card.Delete(m.context, m.cards[m.state.payment.selected].ID)
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-				if len(m.cards)-1 == 0 && m.page == accountPage {
-					m.state.account.focused = false
-				}
-				return m, func() tea.Msg {
-					cards, err := m.client.Card.List(m.context)
+				id := m.cards[m.state.payment.selected].ID
+				m.state.payment.removing = id
+				return m, m.traced("Card.Delete", func(ctx context.Context) tea.Msg {
+					_, err := m.client.Card.Delete(ctx, id)
+					if err != nil {
+						return err
+					}
+					cards, err := m.client.Card.List(ctx)
 					if err != nil {
 						return err
 					}
 					return cards.Data
-				}
+				})
 			}
 			return m, nil
 		case "n":
@@ -343,23 +393,25 @@ func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 			return m, nil
 		}
 	case *stripe.Token:
-		params := terminal.CardNewParams{Token: terminal.F(msg.ID)}
-		response, err := m.client.Card.New(m.context, params)
-		if err != nil {
-			return m, func() tea.Msg { return err }
-		}
-		cards, err := m.client.Card.List(m.context)
-		if err != nil {
-			return m, func() tea.Msg { return err }
-		}
-		m.cards = cards.Data
-		return m, func() tea.Msg {
-			err := m.SetCard(response.Data)
+		token := msg.ID
+		return m, m.traced("Card.New", func(ctx context.Context) tea.Msg {
+			params := terminal.CardNewParams{Token: terminal.F(token)}
+			response, err := m.client.Card.New(ctx, params)
 			if err != nil {
 				return err
 			}
-			return SelectedCardUpdatedMsg{cardID: response.Data}
-		}
+			cards, err := m.client.Card.List(ctx)
+			if err != nil {
+				return err
+			}
+			return CardAddedMsg{cardID: response.Data, cards: cards.Data}
+		})
+	case CardAddedMsg:
+		m.cards = msg.cards
+		cardID := msg.cardID
+		return m, m.traced("Cart.SetCard", func(ctx context.Context) tea.Msg {
+			return m.SetCard(ctx, cardID)
+		})
 
 	}
 
@@ -381,8 +433,8 @@ func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 			zip:    form.GetString("zip"),
 		}
 
-		return m, tea.Batch(func() tea.Msg {
-			result, err := api.StripeCreditCard(&stripe.CardParams{
+		return m, tea.Batch(m.traced("Stripe.Token", func(ctx context.Context) tea.Msg {
+			result, err := api.StripeCreditCard(ctx, &stripe.CardParams{
 				Name:       stripe.String(m.user.User.Name),
 				Number:     stripe.String(getCleanCardNumber(m.state.payment.input.number)),
 				ExpMonth:   stripe.String(m.state.payment.input.month),
@@ -394,17 +446,17 @@ func (m model) paymentFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return err
 			}
 			return result
-		}, func() tea.Msg {
+		}), m.traced("Profile.Update", func(ctx context.Context) tea.Msg {
 			params := terminal.ProfileUpdateParams{
 				Name:  terminal.String(m.user.User.Name),
 				Email: terminal.String(m.user.User.Email),
 			}
-			response, err := m.client.Profile.Update(m.context, params)
+			response, err := m.client.Profile.Update(ctx, params)
 			if err != nil {
 				return err
 			}
 			return response.Data
-		})
+		}))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -414,44 +466,56 @@ func (m model) paymentHttpsUpdate(msg tea.Msg) (model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 
 	m.state.footer.commands = []footerCommand{
-		{key: "esc", value: "back"},
+		{key: "esc", value: "cancel"},
+		{key: "r", value: "new link"},
 	}
 
 	switch msg := msg.(type) {
 	case PollPaymentInitMsg:
-		m.state.payment.url = &msg.paymentUrl
-		m.state.payment.generating = false
-		return m, func() tea.Msg {
-			return PollPaymentStatusMsg{cardCount: len(m.cards)}
+		if msg.poll != m.state.payment.poll {
+			return m, nil
 		}
+		m.state.payment.collection = msg.collection
+		m.state.payment.generating = false
+		return m, m.pollCardCollection(msg.poll)
 	case PollPaymentStatusMsg:
-		return m, tea.Tick(time.Second, func(t time.Time) tea.Msg {
-			cards, err := m.client.Card.List(m.context)
-			if err != nil {
-				return err
-			}
-			if len(cards.Data) > msg.cardCount {
-				return PollPaymentCompleteMsg{cards: cards.Data}
-			}
-			return PollPaymentStatusMsg{cardCount: msg.cardCount}
-		})
+		if msg.poll != m.state.payment.poll {
+			return m, nil
+		}
+		if m.state.payment.collection.Expired(time.Now()) {
+			m.state.payment.expired = true
+			m.state.payment.poll++
+			return m, nil
+		}
+		return m, m.pollCardCollection(msg.poll)
 	case PollPaymentCompleteMsg:
+		// the card is in the list now, where a failure to select it leaves it
+		m.state.payment.poll++
+		m.state.payment.collection = nil
+		m.state.payment.view = paymentListView
+		m.state.payment.submitting = true
 		m.cards = msg.cards
-		m.state.payment.selected = len(m.cards) - 1
-		cardID := m.cards[m.state.payment.selected].ID
-		return m, func() tea.Msg {
-			err := m.SetCard(cardID)
-			if err != nil {
-				return err
+		for i, card := range m.cards {
+			if card.ID == msg.cardID {
+				m.state.payment.selected = i
 			}
-			return SelectedCardUpdatedMsg{cardID: cardID}
 		}
+		cardID := msg.cardID
+		return m, m.traced("Cart.SetCard", func(ctx context.Context) tea.Msg {
+			return m.SetCard(ctx, cardID)
+		})
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
 			m.state.payment.view = paymentListView
+			m.state.payment.collection = nil
+			m.state.payment.poll++
 			return m, nil
+		case "r":
+			if !m.state.payment.generating {
+				return m.startCardCollection()
+			}
 		}
 	}
 
@@ -520,6 +584,9 @@ func (m model) paymentListView() string {
 		if m.state.payment.deleting != nil && *m.state.payment.deleting == i {
 			content = accent("are you sure?") + base(" (y/n)")
 		}
+		if card.ID == m.state.payment.removing {
+			content = base("removing...")
+		}
 
 		focused := i == m.state.payment.selected
 		method := m.CreateBox(m.formatListItem(content, focused), focused)
@@ -562,43 +629,56 @@ func (m model) paymentHttpsView() string {
 	base := m.theme.Base().Render
 	accent := m.theme.TextAccent().Render
 
-	if m.state.payment.url == nil {
+	collection := m.state.payment.collection
+	if m.state.payment.expired {
+		return m.theme.Base().Width(m.widthContent).Render(
+			" " + m.theme.TextError().Render("the payment link expired") + "\n " +
+				base("press r for a new link or esc to go back"),
+		)
+	}
+	if collection == nil {
 		return m.theme.TextError().Render(" failed to generate payment url")
 	}
 
-	qr, _, err := qrfefe.Generate(0, *m.state.payment.url)
+	qr, _, err := qrfefe.Generate(0, collection.URL)
 	if err != nil {
 		return m.theme.TextError().Render(" failed to generate qr code: " + err.Error())
 	}
 
+	elapsed := time.Since(collection.Started).Round(time.Second)
+	remaining := max(collection.Timeout-elapsed, 0).Round(time.Second)
+	status := fmt.Sprintf("waiting for your card, %s (link expires in %s)", elapsed, remaining)
+	if m.state.retrying != nil {
+		status = m.RetryingView()
+	}
+
 	return m.theme.Base().Render(
 		lipgloss.JoinVertical(
 			lipgloss.Center,
 			m.theme.Base().Width(m.widthContent).Render(),
 			qr,
 			base("scan or copy to enter payment information"),
-			accent(*m.state.payment.url),
+			accent(collection.URL),
+			"",
+			base(status),
+			base("closed the page? press r for a new link"),
 		),
 	)
 }
 
 func (m model) paymentCostsView() string {
-	view := strings.Builder{}
-	price := m.cart.Amount.Subtotal
-	shipping := m.cart.Amount.Shipping
-
-	if m.IsSubscribing() {
-		price = m.state.subscribe.product.Variants[m.state.subscribe.selected].Price
-		shipping = 0
+	parts := []string{}
+	for _, line := range m.costLines() {
+		text := line.label + ": " + line.amount
+		if line.label == "shipping" && !m.IsSubscribing() && m.cart.Shipping.Service != "" {
+			text += " (" + m.cart.Shipping.Service + ")"
+		}
+		if line.accent {
+			text = m.theme.TextAccent().Render(text)
+		}
+		parts = append(parts, text)
 	}
 
-	view.WriteString(fmt.Sprintf(" subtotal: %s", formatUSD(int(price))) + ", ")
-	view.WriteString(fmt.Sprintf("shipping: %s", formatUSD(int(shipping))) + ", ")
-	view.WriteString(
-		m.theme.TextAccent().
-			Render(fmt.Sprintf("total: %s", formatUSD(int(price+shipping)))),
-	)
-	view.WriteString("\n")
-
-	return view.String()
+	wrapped := wordWrap(strings.Join(parts, ", "), m.widthContent-1)
+	return " " + strings.ReplaceAll(wrapped, "\n", "\n ") + "\n"
 }
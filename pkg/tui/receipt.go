@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/receipt"
+)
+
+// ReceiptView shows an order as a plain text receipt, unstyled so it can be
+// selected and copied as it is.
+func (m model) ReceiptView(order terminal.Order) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	lines := []string{
+		base("< ") + accent("esc ") + base("back to order"),
+		"",
+		base("select the receipt to copy it, press ") + accent("c") + base(" to copy it to your clipboard, or run"),
+		accent("ssh terminal.shop receipt " + order.ID + " > receipt.md"),
+		"",
+		strings.TrimRight(receipt.New(order, m.products).String(), "\n"),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// copyReceipt copies the receipt of the order being viewed to the clipboard
+// of the customer's terminal, for terminals that support OSC 52.
+func (m model) copyReceipt() tea.Cmd {
+	text := receipt.New(*m.state.orders.detail, m.products).String()
+	output := m.renderer.Output()
+	return func() tea.Msg {
+		output.Copy(text)
+		return nil
+	}
+}
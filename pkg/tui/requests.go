@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+// orderRequestInput is the form for returning a delivered order or reporting
+// a problem with it. items are order item IDs.
+type orderRequestInput struct {
+	kind    string
+	items   []string
+	reason  string
+	details string
+}
+
+type OrderUpdatedMsg struct {
+	order terminal.Order
+}
+
+var requestReasons = []huh.Option[string]{
+	huh.NewOption("arrived damaged", api.ReasonDamaged),
+	huh.NewOption("something's missing", api.ReasonMissing),
+	huh.NewOption("wrong item", api.ReasonWrongItem),
+	huh.NewOption("don't want it anymore", api.ReasonUnwanted),
+	huh.NewOption("something else", api.ReasonOther),
+}
+
+func formatReason(reason string) string {
+	for _, option := range requestReasons {
+		if option.Value == reason {
+			return option.Key
+		}
+	}
+	return strings.ReplaceAll(reason, "_", " ")
+}
+
+// reloadOrder fetches order after the API changed it.
+func (m model) reloadOrder(ctx context.Context, orderID string) tea.Msg {
+	order, err := m.client.Order.Get(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return OrderUpdatedMsg{order: order.Data}
+}
+
+func (m model) cancelOrder() (model, tea.Cmd) {
+	orderID := m.state.orders.detail.ID
+	m.state.orders.cancelling = false
+	m.state.orders.updating = true
+	return m, m.traced("Order.Cancel", func(ctx context.Context) tea.Msg {
+		if err := api.CancelOrder(ctx, m.client, orderID); err != nil {
+			return err
+		}
+		return m.reloadOrder(ctx, orderID)
+	})
+}
+
+// orderUpdated swaps the new copy of an order in wherever it's shown.
+func (m model) orderUpdated(order terminal.Order) model {
+	m.state.orders.updating = false
+	for i := range m.state.orders.list {
+		if m.state.orders.list[i].ID == order.ID {
+			m.state.orders.list[i] = order
+		}
+	}
+	for i := range m.state.spending.orders {
+		if m.state.spending.orders[i].ID == order.ID {
+			m.state.spending.orders[i] = order
+		}
+	}
+	if m.state.orders.detail != nil && m.state.orders.detail.ID == order.ID {
+		m.state.orders.detail = &order
+		if m.page == accountPage && m.state.orders.viewing {
+			m.state.footer.commands = orderDetailCommands(order)
+		}
+	}
+	return m
+}
+
+func (m model) OrderRequestSwitch() (model, tea.Cmd) {
+	order := *m.state.orders.detail
+	m.state.orders.requesting = true
+	m.state.orders.request = orderRequestInput{kind: api.RequestReturn}
+
+	items := []huh.Option[string]{}
+	for _, item := range order.Items {
+		items = append(items, huh.NewOption(m.formatOrderItem(item), item.ID))
+	}
+
+	m.state.orders.requestForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("what would you like to do?").
+				Key("kind").
+				Options(
+					huh.NewOption("return items", api.RequestReturn),
+					huh.NewOption("report a problem", api.RequestProblem),
+				).
+				Value(&m.state.orders.request.kind),
+			huh.NewMultiSelect[string]().
+				Title("which items?").
+				Key("items").
+				Options(items...).
+				Value(&m.state.orders.request.items).
+				Validate(func(items []string) error {
+					if len(items) == 0 {
+						return errors.New("pick at least one item")
+					}
+					return nil
+				}),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("why?").
+				Key("reason").
+				Options(requestReasons...).
+				Value(&m.state.orders.request.reason),
+			huh.NewText().
+				Title("anything else we should know?").
+				Key("details").
+				CharLimit(500).
+				Value(&m.state.orders.request.details).
+				Validate(validate.WithinLen(0, 500, "details")),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "space", value: "pick item"},
+		{key: "enter", value: "next"},
+	}
+	return m, m.state.orders.requestForm.Init()
+}
+
+func (m model) OrderRequestUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" && !m.state.orders.updating {
+			m.state.orders.requesting = false
+			m.state.footer.commands = orderDetailCommands(*m.state.orders.detail)
+			return m, nil
+		}
+	}
+
+	next, cmd := m.state.orders.requestForm.Update(msg)
+	m.state.orders.requestForm = next.(*huh.Form)
+	if !m.state.orders.updating && m.state.orders.requestForm.State == huh.StateCompleted {
+		order := *m.state.orders.detail
+		form := m.state.orders.requestForm
+		items, _ := form.Get("items").([]string)
+		input := orderRequestInput{
+			kind:    form.GetString("kind"),
+			items:   items,
+			reason:  form.GetString("reason"),
+			details: form.GetString("details"),
+		}
+		m.state.orders.request = input
+		params := api.OrderRequestParams{
+			Kind:    input.kind,
+			Reason:  input.reason,
+			Details: strings.TrimSpace(input.details),
+		}
+		for _, item := range order.Items {
+			for _, id := range input.items {
+				if item.ID == id {
+					params.Items = append(params.Items, api.OrderRequestItem{OrderItemID: id, Quantity: item.Quantity})
+				}
+			}
+		}
+
+		m.state.orders.requesting = false
+		m.state.orders.updating = true
+		m.state.footer.commands = orderDetailCommands(order)
+		return m, m.traced("Order.Request", func(ctx context.Context) tea.Msg {
+			if err := api.NewOrderRequest(ctx, m.client, order.ID, params); err != nil {
+				return err
+			}
+			return m.reloadOrder(ctx, order.ID)
+		})
+	}
+	return m, cmd
+}
+
+func (m model) OrderRequestView(totalWidth int) string {
+	return m.state.orders.requestForm.WithWidth(totalWidth).View()
+}
+
+// orderRequestLines describes the returns and problem reports made about an
+// order, with their status.
+func (m model) orderRequestLines(order terminal.Order) []string {
+	base := m.theme.Base().Render
+	highlight := m.theme.TextBrand().Render
+
+	lines := []string{}
+	for _, request := range api.OrderRequests(order) {
+		kind := "return"
+		if request.Kind == api.RequestProblem {
+			kind = "problem report"
+		}
+		lines = append(lines, base(kind+": "+formatReason(request.Reason)+" · ")+highlight(request.Status))
+		for _, requested := range request.Items {
+			for _, item := range order.Items {
+				if item.ID == requested.OrderItemID {
+					lines = append(lines, base("  "+m.formatOrderItem(terminal.OrderItem{
+						Quantity:         requested.Quantity,
+						ProductVariantID: item.ProductVariantID,
+					})))
+				}
+			}
+		}
+		if request.Details != "" {
+			lines = append(lines, base("  \""+request.Details+"\""))
+		}
+	}
+	return lines
+}
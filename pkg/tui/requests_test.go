@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestOrderRequestForm(t *testing.T) {
+	order := terminal.Order{
+		ID:       "ord_1",
+		Tracking: terminal.OrderTracking{Status: "DELIVERED"},
+		Items: []terminal.OrderItem{
+			{ID: "itm_1", Quantity: 2, ProductVariantID: "var_1"},
+			{ID: "itm_2", Quantity: 1, ProductVariantID: "var_2"},
+		},
+	}
+	tm := newTestModel(t, nil)
+	tm.m.products = []terminal.Product{
+		{ID: "prd_1", Name: "segfault", Variants: []terminal.ProductVariant{{ID: "var_1"}}},
+		{ID: "prd_2", Name: "cron", Variants: []terminal.ProductVariant{{ID: "var_2"}}},
+	}
+	tm.switchTo(tm.m.OrderDetailSwitch(order))
+
+	tm.press("t")
+	if !tm.m.state.orders.requesting {
+		t.Fatal("the request form didn't open")
+	}
+	// report a problem with the second item, which is missing
+	tm.press("down", "enter", "down", " ", "enter")
+	tm.press("down", "enter")
+	tm.press("please resend asap")
+	if tm.m.page != accountPage || tm.quit {
+		t.Fatalf("typing the details left the form for page %d", tm.m.page)
+	}
+	tm.press("enter")
+
+	request, ok := tm.api.request("POST", "/order/ord_1/request")
+	if !ok {
+		t.Fatal("the request wasn't sent")
+	}
+	params := api.OrderRequestParams{}
+	if err := json.Unmarshal([]byte(request.body), &params); err != nil {
+		t.Fatal(err)
+	}
+	want := api.OrderRequestParams{
+		Kind:    api.RequestProblem,
+		Reason:  api.ReasonMissing,
+		Details: "please resend asap",
+		Items:   []api.OrderRequestItem{{OrderItemID: "itm_2", Quantity: 1}},
+	}
+	if params.Kind != want.Kind || params.Reason != want.Reason || params.Details != want.Details ||
+		len(params.Items) != 1 || params.Items[0] != want.Items[0] {
+		t.Errorf("got %+v, want %+v", params, want)
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	order := terminal.Order{ID: "ord_1"}
+	tm := newTestModel(t, nil)
+	tm.switchTo(tm.m.OrderDetailSwitch(order))
+
+	tm.press("x", "n")
+	if _, ok := tm.api.request("POST", "/order/ord_1/cancel"); ok {
+		t.Fatal("cancelled without confirming")
+	}
+	tm.press("x", "y")
+	if _, ok := tm.api.request("POST", "/order/ord_1/cancel"); !ok {
+		t.Fatal("the order wasn't cancelled")
+	}
+}
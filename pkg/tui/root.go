@@ -3,7 +3,9 @@ package tui
 import (
 	"context"
 	"math"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -11,6 +13,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/recording"
+	"github.com/terminaldotshop/terminal/go/pkg/sessions"
 	"github.com/terminaldotshop/terminal/go/pkg/tui/theme"
 )
 
@@ -35,6 +40,10 @@ const (
 	ordersPage
 	aboutPage
 	faqPage
+	keysPage
+	claimPage
+	spendingPage
+	supportPage
 )
 
 const (
@@ -62,26 +71,31 @@ type model struct {
 	cards         []terminal.Card
 	subscriptions []terminal.Subscription
 	tokens        []terminal.Token
+	keys          []api.Key
 	apps          []terminal.App
-	orders        []terminal.Order
+	tickets       []api.Ticket
+	codes         []api.CartCode
 	order         *terminal.Order
 	cart          terminal.Cart
 	subscription  terminal.SubscriptionParam
 	renderer      *lipgloss.Renderer
 	// output          *termenv.Output
-	theme           theme.Theme
-	fingerprint     string
-	anonymous       bool
-	viewportWidth   int
-	viewportHeight  int
-	widthContainer  int
-	heightContainer int
-	widthContent    int
-	heightContent   int
-	size            size
-	accessToken     string
-	faqs            []FAQ
-	error           *VisibleError
+	theme             theme.Theme
+	fingerprint       string
+	legacyFingerprint string
+	anonymous         bool
+	viewportWidth     int
+	viewportHeight    int
+	widthContainer    int
+	heightContainer   int
+	widthContent      int
+	heightContent     int
+	size              size
+	accessToken       string
+	faqs              []FAQ
+	error             *VisibleError
+	status            sessions.Status
+	location          *time.Location
 }
 
 type VisibleError struct {
@@ -94,8 +108,12 @@ type state struct {
 	shipping      shippingState
 	subscriptions subscriptionsState
 	tokens        tokensState
+	keys          keysState
+	claim         claimState
 	apps          appsState
 	orders        ordersState
+	support       supportState
+	spending      spendingState
 	shop          shopState
 	account       accountState
 	footer        footerState
@@ -105,42 +123,51 @@ type state struct {
 	confirm       confirmState
 	menu          menuState
 	finalSub      finalSubState
+	final         finalState
+	codes         codesState
+	gift          giftState
+	vat           vatState
+	retrying      *api.Retry
 }
 
 type children struct {
 }
 
 func NewModel(
+	ctx context.Context,
 	renderer *lipgloss.Renderer,
 	fingerprint string,
+	legacyFingerprint string,
 	anonymous bool,
 	clientIP *string,
 	command []string,
 ) (tea.Model, error) {
-	api.Init()
-
-	ctx := context.Background()
 	ctx = context.WithValue(ctx, "client_ip", clientIP)
 
 	result := model{
 		command:  command,
 		context:  ctx,
+		location: timezoneFromContext(ctx),
 		region:   nil,
 		page:     splashPage,
 		renderer: renderer,
 		// output:      renderer.Output(),
-		fingerprint: fingerprint,
-		anonymous:   anonymous,
-		theme:       theme.BasicTheme(renderer, nil),
-		faqs:        LoadFaqs(),
+		fingerprint:       fingerprint,
+		legacyFingerprint: legacyFingerprint,
+		anonymous:         anonymous,
+		theme:             theme.BasicTheme(renderer, nil),
+		faqs:              LoadFaqs(),
 		accountPages: []page{
 			ordersPage,
+			spendingPage,
 			subscriptionsPage,
 			tokensPage,
+			keysPage,
 			appsPage,
 			// shippingPage,
 			// paymentPage,
 			faqPage,
+			supportPage,
 			aboutPage,
 		},
 		subscription: terminal.SubscriptionParam{},
@@ -164,6 +191,9 @@ func NewModel(
 			tokens: tokensState{
 				selected: 0,
 			},
+			keys: keysState{
+				selected: 0,
+			},
 			orders: ordersState{
 				selected: 0,
 			},
@@ -180,6 +210,13 @@ func NewModel(
 			},
 		},
 	}
+	if anonymous {
+		result.accountPages = slices.DeleteFunc(result.accountPages, func(p page) bool {
+			return p == keysPage
+		})
+		result.accountPages = append([]page{claimPage}, result.accountPages...)
+	}
+	sessions.FromContext(ctx).SetPage(pageNames[result.page])
 	return result, nil
 }
 
@@ -188,11 +225,25 @@ func (m model) Init() tea.Cmd {
 }
 
 func (m model) SwitchPage(page page) model {
+	logger.FromContext(m.context).Debug("switch page", "from", pageNames[m.page], "to", pageNames[page])
+	sessions.FromContext(m.context).SetPage(pageNames[page])
 	m.page = page
 	m.switched = true
 	return m
 }
 
+// removed finishes a row removal once the reloaded list arrives, leaving an
+// account page that is now empty. The caller clears its own removing field.
+func (m model) removed(removing string, remaining int) model {
+	if removing == "" {
+		return m
+	}
+	if remaining == 0 && m.page == accountPage {
+		m.state.account.focused = false
+	}
+	return m
+}
+
 func (m model) InitialDataLoaded() (model, tea.Cmd) {
 	if len(m.command) == 0 {
 		return m.ShopSwitch()
@@ -216,6 +267,7 @@ func (m model) InitialDataLoaded() (model, tea.Cmd) {
 		"orders",
 		"subscriptions",
 		"tokens",
+		"keys",
 		"apps",
 		"faq",
 		"about",
@@ -235,6 +287,9 @@ func (m model) InitialDataLoaded() (model, tea.Cmd) {
 				} else if name == "tokens" && page == tokensPage {
 					selected = index
 					break
+				} else if name == "keys" && page == keysPage {
+					selected = index
+					break
 				} else if name == "apps" && page == appsPage {
 					selected = index
 					break
@@ -258,6 +313,16 @@ func (m model) InitialDataLoaded() (model, tea.Cmd) {
 	return m.ShopSwitch()
 }
 
+// formFocused is true while the account page shows a form, whose typing the
+// header mustn't take as hotkeys.
+func (m model) formFocused() bool {
+	return m.state.apps.editing ||
+		m.state.orders.filtering ||
+		m.state.orders.requesting ||
+		m.state.support.opening ||
+		m.state.support.replying
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 
@@ -265,17 +330,43 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case VisibleError:
 		m.error = &msg
 	case error:
+		if m.context.Err() != nil {
+			// the session is over, so whatever failed was abandoned
+			return m, nil
+		}
+		logger.FromContext(m.context).Error("command failed", "page", m.page, "error", msg)
 		m.error = &VisibleError{
 			message: api.GetErrorMessage(msg),
 		}
+		m.state.claim.generating = false
+		m.state.tokens.removing = ""
+		m.state.subscriptions.removing = ""
+		m.state.apps.removing = ""
+		m.state.shipping.removing = ""
+		m.state.payment.removing = ""
+		m.state.keys.removing = ""
+		m.state.codes.removing = ""
+		m.state.codes.applying = false
+		m.state.codes.entering = false
+		m.state.gift.saving = false
+		m.state.gift.editing = false
+		m.state.confirm.saving = false
+		m.state.confirm.noting = false
+		m.state.vat.saving = false
+		m.state.vat.editing = false
+		m.state.orders.loading = false
+		m.state.orders.updating = false
+		m.state.spending.loading = false
+		m.state.support.loading = false
+		m.state.support.sending = false
 		if m.page == shopPage || m.page == cartPage {
-			cmds = append(cmds, func() tea.Msg {
-				response, err := m.client.Cart.Get(m.context)
+			cmds = append(cmds, m.traced("Cart.Get", func(ctx context.Context) tea.Msg {
+				response, err := m.client.Cart.Get(ctx)
 				if err != nil {
 					return VisibleError{message: "something went wrong, restart the ssh session"}
 				}
 				return response.Data
-			})
+			}))
 		}
 	case tea.WindowSizeMsg:
 		m.viewportWidth = msg.Width
@@ -322,6 +413,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state.cart.lastUpdateID == msg.updateID {
 			m.cart = msg.updated
 		}
+	case sessions.Status:
+		m = m.StatusUpdate(msg)
+	case RetryingMsg:
+		m.state.retrying = msg.retry
+	case ClaimCodeMsg:
+		m.state.claim.generating = false
+		m.state.claim.code = &msg.code
 	case terminal.ViewInitResponseData:
 		m.user = msg.Profile
 		m.products = msg.Products
@@ -331,7 +429,6 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.subscriptions = msg.Subscriptions
 		m.tokens = msg.Tokens
 		m.apps = msg.Apps
-		m.orders = msg.Orders
 		m.region = &msg.Region
 		m = m.reorderProducts()
 	case terminal.Profile:
@@ -343,16 +440,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cart = msg
 	case []terminal.Card:
 		m.cards = msg
+		m = m.removed(m.state.payment.removing, len(msg))
+		m.state.payment.removing = ""
 	case []terminal.Address:
 		m.addresses = msg
+		m = m.removed(m.state.shipping.removing, len(msg))
+		m.state.shipping.removing = ""
 	case []terminal.Subscription:
 		m.subscriptions = msg
+		m = m.removed(m.state.subscriptions.removing, len(msg))
+		m.state.subscriptions.removing = ""
 	case []terminal.Token:
 		m.tokens = msg
+		m = m.removed(m.state.tokens.removing, len(msg))
+		m.state.tokens.removing = ""
+	case []api.Key:
+		m.keys = msg
+		m = m.removed(m.state.keys.removing, len(msg))
+		m.state.keys.removing = ""
 	case []terminal.App:
 		m.apps = msg
-	case []terminal.Order:
-		m.orders = msg
+		m = m.removed(m.state.apps.removing, len(msg))
+		m.state.apps.removing = ""
+	case OrdersPageMsg:
+		m = m.ordersLoaded(msg)
+	case SpendingOrdersMsg:
+		m.state.spending.orders = msg.orders
+		m.state.spending.loading = false
+		m.state.spending.loaded = true
+	case OrderUpdatedMsg:
+		m = m.orderUpdated(msg.order)
+	case []api.Ticket:
+		m.tickets = msg
+		m.state.support.loading = false
+		m.state.support.selected = min(m.state.support.selected, len(msg))
+	case SupportOrdersMsg:
+		m.state.support.orders = msg.orders
+	case CartCodesMsg:
+		m.codes = msg.codes
 	}
 
 	var cmd tea.Cmd
@@ -392,7 +517,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	m.hasMenu = m.page == shopPage ||
-		(m.page == accountPage && m.state.apps.editing == false)
+		(m.page == accountPage && !m.formFocused())
 		// m.page == aboutPage ||
 		// m.page == faqPage
 
@@ -406,6 +531,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.switched = false
 	}
 
+	// Keep card numbers and CVCs out of session recordings.
+	recording.FromContext(m.context).SetRedacted(
+		m.page == paymentPage && m.state.payment.view == paymentFormView,
+	)
+
 	return m, tea.Batch(cmds...)
 }
 
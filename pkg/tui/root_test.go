@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+)
+
+// testRequest is a request the test API received.
+type testRequest struct {
+	method string
+	path   string
+	query  string
+	body   string
+}
+
+// testAPI answers with responses, keyed by method and path like "GET /cart",
+// or with the query too for a particular page, and with an empty body
+// otherwise.
+type testAPI struct {
+	mu        sync.Mutex
+	responses map[string]string
+	requests  []testRequest
+}
+
+func (a *testAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	a.mu.Lock()
+	a.requests = append(a.requests, testRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: string(body)})
+	response, ok := a.responses[r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery]
+	if !ok {
+		response, ok = a.responses[r.Method+" "+r.URL.Path]
+	}
+	a.mu.Unlock()
+	if !ok {
+		response = `{"data": null}`
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, response)
+}
+
+// request returns the last request made to path with method.
+func (a *testAPI) request(method, path string) (testRequest, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := len(a.requests) - 1; i >= 0; i-- {
+		if a.requests[i].method == method && a.requests[i].path == path {
+			return a.requests[i], true
+		}
+	}
+	return testRequest{}, false
+}
+
+// count returns how many requests were made to path with method.
+func (a *testAPI) count(method, path string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	count := 0
+	for _, request := range a.requests {
+		if request.method == method && request.path == path {
+			count++
+		}
+	}
+	return count
+}
+
+// testModel runs a signed in model against the test API the way a session
+// would, feeding what its commands return back into it.
+type testModel struct {
+	t    *testing.T
+	m    model
+	api  *testAPI
+	msgs chan tea.Msg
+	done chan struct{}
+	quit bool
+}
+
+func newTestModel(t *testing.T, responses map[string]string) *testModel {
+	t.Helper()
+	fake := &testAPI{responses: responses}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	next, err := NewModel(context.Background(), lipgloss.NewRenderer(io.Discard), "", "", false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := &testModel{
+		t:    t,
+		m:    next.(model),
+		api:  fake,
+		msgs: make(chan tea.Msg),
+		done: make(chan struct{}),
+	}
+	t.Cleanup(func() { close(tm.done) })
+	tm.m.client = terminal.NewClient(
+		option.WithBaseURL(server.URL+"/"),
+		option.WithBearerToken("test"),
+		option.WithMaxRetries(0),
+	)
+	tm.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return tm
+}
+
+// switchTo applies a page switch, e.g. tm.m.AccountSwitch, as if a key had
+// opened it.
+func (tm *testModel) switchTo(next model, cmd tea.Cmd) {
+	tm.m = next
+	tm.run(cmd)
+	tm.settle()
+}
+
+// send updates the model with msg and waits for what its commands return
+// straight away, leaving out ticks.
+func (tm *testModel) send(msg tea.Msg) {
+	tm.update(msg)
+	tm.settle()
+}
+
+// press sends each key, by name for the special ones like "enter", and types
+// anything else a key at a time.
+func (tm *testModel) press(keys ...string) {
+	special := map[string]tea.KeyType{
+		"enter": tea.KeyEnter,
+		"esc":   tea.KeyEsc,
+		"tab":   tea.KeyTab,
+		"up":    tea.KeyUp,
+		"down":  tea.KeyDown,
+		"left":  tea.KeyLeft,
+		"right": tea.KeyRight,
+	}
+	for _, key := range keys {
+		if keyType, ok := special[key]; ok {
+			tm.send(tea.KeyMsg{Type: keyType})
+			continue
+		}
+		for _, r := range key {
+			tm.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+}
+
+func (tm *testModel) update(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			tm.run(cmd)
+		}
+		return
+	case tea.QuitMsg:
+		tm.quit = true
+		return
+	}
+	next, cmd := tm.m.Update(msg)
+	tm.m = next.(model)
+	tm.run(cmd)
+}
+
+func (tm *testModel) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		select {
+		case tm.msgs <- msg:
+		case <-tm.done:
+		}
+	}()
+}
+
+// settle takes in messages until none has come for a while, which ticks
+// like the cursor's blink take longer than.
+func (tm *testModel) settle() {
+	for {
+		select {
+		case msg := <-tm.msgs:
+			if msg != nil {
+				tm.update(msg)
+			}
+		case <-time.After(50 * time.Millisecond):
+			return
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package tui
 
 import (
+	"context"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal-sdk-go/option"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
 	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
 )
 
@@ -15,6 +19,7 @@ type shippingView = int
 const (
 	shippingListView shippingView = iota
 	shippingFormView
+	shippingMethodView
 )
 
 type shippingInput struct {
@@ -26,19 +31,34 @@ type shippingInput struct {
 	country  string
 	zip      string
 	phone    string
+	// instructions are for the courier, e.g. where to leave the parcel
+	instructions string
 }
 
 type shippingState struct {
 	view       shippingView
 	selected   int
 	deleting   *int
+	removing   string
 	input      shippingInput
 	form       *huh.Form
 	submitting bool
+	// rates are the ways the cart can ship to the chosen address, offered
+	// when there's more than one.
+	rates []api.ShippingRate
+	rate  int
 }
 
 type SelectedShippingUpdatedMsg struct {
 	shippingID string
+	cart       *terminal.Cart
+	rates      []api.ShippingRate
+	// tax is estimated for subscriptions, a cart's comes with it
+	tax *api.Tax
+}
+
+type ShippingRateSelectedMsg struct {
+	cart terminal.Cart
 }
 
 type ShippingAddressAddedMsg struct {
@@ -54,14 +74,27 @@ func (m model) ShippingSwitch() (model, tea.Cmd) {
 		{key: "x/del", value: "remove"},
 		{key: "enter", value: "select"},
 	}
+	if !m.IsSubscribing() {
+		m.state.footer.commands = append(m.state.footer.commands, footerCommand{key: "g", value: "gift"})
+	}
 	m.state.shipping.submitting = false
+
+	// a gift goes to someone other than the buyer
+	name := huh.NewInput().
+		Title("name").
+		Key("name").
+		Value(&m.user.User.Name).
+		Validate(validate.NotEmpty("name"))
+	if m.Gift() != nil {
+		name = huh.NewInput().
+			Title("recipient name").
+			Key("name").
+			Value(&m.state.shipping.input.name).
+			Validate(validate.NotEmpty("recipient name"))
+	}
 	m.state.shipping.form = huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Title("name").
-				Key("name").
-				Value(&m.user.User.Name).
-				Validate(validate.NotEmpty("name")),
+			name,
 			huh.NewInput().
 				Title("street 1").
 				Key("street1").
@@ -96,6 +129,11 @@ func (m model) ShippingSwitch() (model, tea.Cmd) {
 				Key("zip").
 				Value(&m.state.shipping.input.zip).
 				Validate(validate.NotEmpty("postal code")),
+			huh.NewInput().
+				Title("delivery instructions").
+				Key("instructions").
+				Value(&m.state.shipping.input.instructions).
+				Validate(validate.WithinLen(0, 200, "delivery instructions")),
 		),
 	).
 		WithTheme(m.theme.Form()).
@@ -145,17 +183,76 @@ func (m model) previousAddress() (model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) SetShipping(shippingID string) error {
+// SetShipping ships the cart to shippingID and returns the cart repriced for
+// it, with the rates it can ship at. Subscriptions take the address at
+// checkout, so there's no cart to set, only tax to estimate.
+func (m model) SetShipping(ctx context.Context, shippingID string) tea.Msg {
 	if m.IsSubscribing() {
-		return nil
+		price := m.state.subscribe.product.Variants[m.state.subscribe.selected].Price
+		tax, err := api.EstimateTax(ctx, m.client, shippingID, price)
+		if err != nil {
+			logger.FromContext(ctx).Warn("could not estimate tax", "error", err)
+		}
+		return SelectedShippingUpdatedMsg{shippingID: shippingID, tax: tax}
 	}
 
 	params := terminal.CartSetAddressParams{AddressID: terminal.F(shippingID)}
-	_, err := m.client.Cart.SetAddress(m.context, params)
+	_, err := m.client.Cart.SetAddress(ctx, params)
 	if err != nil {
 		return err
 	}
-	return nil
+	cart, err := m.client.Cart.Get(ctx)
+	if err != nil {
+		return err
+	}
+	// without rates the cart keeps the shipping the API chose
+	rates, err := api.ListShippingRates(ctx, m.client)
+	if err != nil {
+		logger.FromContext(ctx).Warn("could not load shipping rates", "error", err)
+	}
+	return SelectedShippingUpdatedMsg{shippingID: shippingID, cart: &cart.Data, rates: rates}
+}
+
+func (m model) ShippingMethodSwitch(rates []api.ShippingRate) (model, tea.Cmd) {
+	m.state.shipping.view = shippingMethodView
+	m.state.shipping.rates = rates
+	m.state.shipping.rate = api.CurrentRate(rates, m.cart.Shipping)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "back"},
+		{key: "↑/↓", value: "methods"},
+		{key: "enter", value: "select"},
+	}
+	return m, nil
+}
+
+func (m model) shippingMethodUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down", "tab":
+			m.state.shipping.rate = min(m.state.shipping.rate+1, len(m.state.shipping.rates)-1)
+			return m, nil
+		case "k", "up", "shift+tab":
+			m.state.shipping.rate = max(m.state.shipping.rate-1, 0)
+			return m, nil
+		case "enter":
+			rate := m.state.shipping.rates[m.state.shipping.rate]
+			m.state.shipping.submitting = true
+			return m, m.traced("Cart.SetShipping", func(ctx context.Context) tea.Msg {
+				if err := api.SetShippingRate(ctx, m.client, rate.ID); err != nil {
+					return err
+				}
+				cart, err := m.client.Cart.Get(ctx)
+				if err != nil {
+					return err
+				}
+				return ShippingRateSelectedMsg{cart: cart.Data}
+			})
+		case "esc":
+			return m.ShippingSwitch()
+		}
+	}
+	return m, nil
 }
 
 func (m model) GetSelectedAddress() *terminal.Address {
@@ -181,13 +278,9 @@ func (m model) chooseAddress() (model, tea.Cmd) {
 		shippingID := m.addresses[m.state.shipping.selected].ID
 
 		m.state.shipping.submitting = true
-		return m, func() tea.Msg {
-			err := m.SetShipping(shippingID)
-			if err != nil {
-				return err
-			}
-			return SelectedShippingUpdatedMsg{shippingID: shippingID}
-		}
+		return m, m.traced("Cart.SetAddress", func(ctx context.Context) tea.Msg {
+			return m.SetShipping(ctx, shippingID)
+		})
 	} else { // new
 		m.state.shipping.input = shippingInput{country: "US"}
 		m.state.shipping.view = shippingFormView
@@ -211,27 +304,26 @@ func (m model) shippingListUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.previousAddress()
 			}
 		case "delete", "d", "backspace", "x":
-			if m.state.shipping.deleting == nil && m.state.shipping.selected < len(m.addresses) {
+			if m.state.shipping.deleting == nil && m.state.shipping.removing == "" && m.state.shipping.selected < len(m.addresses) {
 				m.state.shipping.deleting = &m.state.shipping.selected
 			}
 			return m, nil
 		case "y":
 			if m.state.shipping.deleting != nil {
 				m.state.shipping.deleting = nil
-				_, err := m.client.Address.Delete(m.context, m.addresses[m.state.shipping.selected].ID)
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-				if len(m.addresses)-1 == 0 && m.page == accountPage {
-					m.state.account.focused = false
-				}
-				return m, func() tea.Msg {
-					shipping, err := m.client.Address.List(m.context)
+				id := m.addresses[m.state.shipping.selected].ID
+				m.state.shipping.removing = id
+				return m, m.traced("Address.Delete", func(ctx context.Context) tea.Msg {
+					_, err := m.client.Address.Delete(ctx, id)
 					if err != nil {
 						return err
 					}
-					return shipping.Data
-				}
+					addresses, err := m.client.Address.List(ctx)
+					if err != nil {
+						return err
+					}
+					return addresses.Data
+				})
 			}
 			return m, nil
 		case "n":
@@ -241,6 +333,10 @@ func (m model) shippingListUpdate(msg tea.Msg) (model, tea.Cmd) {
 			if m.state.shipping.deleting == nil {
 				return m.chooseAddress()
 			}
+		case "g":
+			if m.page == shippingPage && m.state.shipping.deleting == nil && !m.IsSubscribing() {
+				return m.GiftSwitch()
+			}
 		case "esc":
 			if m.state.shipping.deleting != nil {
 				m.state.shipping.deleting = nil
@@ -272,14 +368,10 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 	case ShippingAddressAddedMsg:
 		m.addresses = msg.addresses
 
-		return m, func() tea.Msg {
-			err := m.SetShipping(msg.shippingID)
-			if err != nil {
-				return err
-			}
-
-			return SelectedShippingUpdatedMsg{shippingID: msg.shippingID}
-		}
+		shippingID := msg.shippingID
+		return m, m.traced("Cart.SetAddress", func(ctx context.Context) tea.Msg {
+			return m.SetShipping(ctx, shippingID)
+		})
 	}
 
 	m = m.updateShippingForm()
@@ -292,17 +384,18 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 
 		form := m.state.shipping.form
 		m.state.shipping.input = shippingInput{
-			name:     form.GetString("name"),
-			street1:  form.GetString("street1"),
-			street2:  form.GetString("street2"),
-			city:     form.GetString("city"),
-			province: form.GetString("province"),
-			country:  form.GetString("country"),
-			zip:      form.GetString("zip"),
-			phone:    form.GetString("phone"),
+			name:         form.GetString("name"),
+			street1:      form.GetString("street1"),
+			street2:      form.GetString("street2"),
+			city:         form.GetString("city"),
+			province:     form.GetString("province"),
+			country:      form.GetString("country"),
+			zip:          form.GetString("zip"),
+			phone:        form.GetString("phone"),
+			instructions: form.GetString("instructions"),
 		}
 
-		return m, func() tea.Msg {
+		return m, m.traced("Address.New", func(ctx context.Context) tea.Msg {
 			if m.state.shipping.input.country != "US" && m.state.shipping.input.phone == "" {
 				return VisibleError{message: "phone is required for international orders"}
 			}
@@ -317,11 +410,15 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 				Zip:      terminal.String(m.state.shipping.input.zip),
 				Phone:    terminal.String(m.state.shipping.input.phone),
 			}
-			response, err := m.client.Address.New(m.context, params)
+			opts := []option.RequestOption{}
+			if instructions := m.state.shipping.input.instructions; instructions != "" {
+				opts = append(opts, api.WithDeliveryInstructions(instructions))
+			}
+			response, err := m.client.Address.New(ctx, params, opts...)
 			if err != nil {
 				return err
 			}
-			addresses, err := m.client.Address.List(m.context)
+			addresses, err := m.client.Address.List(ctx)
 			if err != nil {
 				return err
 			}
@@ -329,7 +426,7 @@ func (m model) shippingFormUpdate(msg tea.Msg) (model, tea.Cmd) {
 				shippingID: response.Data,
 				addresses:  addresses.Data,
 			}
-		}
+		})
 	}
 
 	return m, tea.Batch(cmds...)
@@ -340,25 +437,47 @@ func (m model) ShippingUpdate(msg tea.Msg) (model, tea.Cmd) {
 	case error:
 		current := m.state.shipping.view
 		m, cmd := m.ShippingSwitch()
+		if current == shippingMethodView {
+			return m.ShippingMethodSwitch(m.state.shipping.rates)
+		}
 		m.state.shipping.view = current
 		return m, cmd
+	case GiftSavedMsg:
+		m.cart = msg.cart
+		m.state.gift = giftState{}
+		return m.ShippingSwitch()
 	case SelectedShippingUpdatedMsg:
 		if m.IsSubscribing() {
 			m.subscription.AddressID = terminal.String(msg.shippingID)
+			m.state.subscribe.tax = msg.tax
 		} else {
-			m.cart.AddressID = msg.shippingID
-			cart, err := m.client.Cart.Get(m.context)
-			if err != nil {
-				return m, func() tea.Msg { return err }
+			m.cart = *msg.cart
+			if len(msg.rates) > 1 {
+				m.state.shipping.submitting = false
+				return m.ShippingMethodSwitch(msg.rates)
+			}
+			if m.paidByGiftCard() {
+				return m.ConfirmSwitch()
 			}
-			m.cart = cart.Data
+		}
+		return m.PaymentSwitch()
+	case ShippingRateSelectedMsg:
+		m.cart = msg.cart
+		if m.paidByGiftCard() {
+			return m.ConfirmSwitch()
 		}
 		return m.PaymentSwitch()
 	}
 
-	if m.state.shipping.view == shippingListView {
+	if m.page == shippingPage && m.state.gift.editing {
+		return m.GiftUpdate(msg)
+	}
+	switch m.state.shipping.view {
+	case shippingListView:
 		return m.shippingListUpdate(msg)
-	} else {
+	case shippingMethodView:
+		return m.shippingMethodUpdate(msg)
+	default:
 		return m.shippingFormUpdate(msg)
 	}
 }
@@ -368,9 +487,15 @@ func (m model) ShippingView(totalWidth int, focused bool) string {
 		return m.theme.Base().Width(totalWidth).Render(" calculating shipping costs...")
 	}
 
-	if m.state.shipping.view == shippingListView {
+	if m.page == shippingPage && m.state.gift.editing {
+		return m.GiftView()
+	}
+	switch m.state.shipping.view {
+	case shippingListView:
 		return m.shippingListView(totalWidth, focused)
-	} else {
+	case shippingMethodView:
+		return m.shippingMethodView(totalWidth)
+	default:
 		return m.shippingFormView()
 	}
 }
@@ -417,7 +542,11 @@ func (m model) formatAddress(address terminal.Address, focused bool) string {
 	parts = append(parts, address.City+", "+address.Province+", "+address.Country+", ")
 	parts = append(parts, address.Zip)
 
-	return m.formatListItem(lipgloss.JoinHorizontal(lipgloss.Left, parts...), focused)
+	text := lipgloss.JoinHorizontal(lipgloss.Left, parts...)
+	if instructions := api.AddressInstructions(address); instructions != "" {
+		text += "\n     " + m.theme.Base().Render("instructions: "+instructions)
+	}
+	return m.formatListItem(text, focused)
 }
 
 func (m model) shippingListView(totalWidth int, focused bool) string {
@@ -433,6 +562,9 @@ func (m model) shippingListView(totalWidth int, focused bool) string {
 		if m.state.shipping.deleting != nil && *m.state.shipping.deleting == i {
 			content = m.formatListItem(accent("are you sure?")+base(" (y/n)"), true)
 		}
+		if address.ID == m.state.shipping.removing {
+			content = base("removing...")
+		}
 		box := m.CreateBoxCustom(
 			content,
 			i == m.state.shipping.selected && (focused || m.page != accountPage),
@@ -450,13 +582,39 @@ func (m model) shippingListView(totalWidth int, focused bool) string {
 	addresses = append(addresses, newAddress)
 	addressList := lipgloss.JoinVertical(lipgloss.Left, addresses...)
 
+	title := " select shipping address"
+	if gift := m.Gift(); gift != nil && m.page == shippingPage {
+		title = " select the recipient's address\n " + m.GiftSummaryView(gift, totalWidth-2)
+	}
+
 	return m.theme.Base().Render(lipgloss.JoinVertical(
 		lipgloss.Left,
-		" select shipping address",
+		title,
 		addressList,
 	))
 }
 
+func (m model) shippingMethodView(totalWidth int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	methods := []string{}
+	for i, rate := range m.state.shipping.rates {
+		selected := i == m.state.shipping.rate
+		text := rate.Service + "  " + accent(formatUSD(int(rate.Amount)))
+		if rate.Timeframe != "" {
+			text += "\n     " + base(rate.Timeframe)
+		}
+		methods = append(methods, m.CreateBoxCustom(m.formatListItem(text, selected), selected, totalWidth))
+	}
+
+	return m.theme.Base().Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		" select shipping method",
+		lipgloss.JoinVertical(lipgloss.Left, methods...),
+	))
+}
+
 func (m model) shippingFormView() string {
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+func TestShippingRates(t *testing.T) {
+	rates := []api.ShippingRate{
+		{ID: "rate_1", Service: "USPS Ground Advantage", Amount: 500},
+		{ID: "rate_2", Service: "USPS Priority Mail", Amount: 1200},
+		{ID: "rate_3", Service: "USPS Priority Mail Express", Amount: 3000},
+	}
+	products := []terminal.Product{{ID: "prd_1", Name: "segfault", Variants: []terminal.ProductVariant{{ID: "var_1"}}}}
+	cart := terminal.Cart{
+		Items:    []terminal.CartItem{{ID: "itm_1", ProductVariantID: "var_1", Quantity: 1}},
+		Shipping: terminal.CartShipping{Service: "USPS Priority Mail"},
+	}
+
+	t.Run("one rate", func(t *testing.T) {
+		tm := newTestModel(t, nil)
+		tm.m.products = products
+		tm.m = tm.m.SwitchPage(shippingPage)
+		tm.send(SelectedShippingUpdatedMsg{shippingID: "shp_1", cart: &cart, rates: rates[:1]})
+		if tm.m.page != paymentPage {
+			t.Errorf("got page %d, want the payment page without a choice of rates", tm.m.page)
+		}
+	})
+
+	t.Run("choose", func(t *testing.T) {
+		tm := newTestModel(t, map[string]string{
+			"GET /cart": `{"data": {"items": [{"id": "itm_1", "productVariantID": "var_1", "quantity": 1}]}}`,
+		})
+		tm.m.products = products
+		tm.m = tm.m.SwitchPage(shippingPage)
+		tm.send(SelectedShippingUpdatedMsg{shippingID: "shp_1", cart: &cart, rates: rates})
+		if tm.m.state.shipping.view != shippingMethodView {
+			t.Fatal("the rates weren't offered")
+		}
+		if tm.m.state.shipping.rate != 1 {
+			t.Errorf("got rate %d selected, want the cart's current one", tm.m.state.shipping.rate)
+		}
+
+		tm.press("down", "down", "enter")
+		request, ok := tm.api.request("PUT", "/cart/shipping")
+		if !ok {
+			t.Fatal("the rate wasn't set")
+		}
+		if want := `{"rateID":"rate_3"}`; request.body != want {
+			t.Errorf("got %s, want %s", request.body, want)
+		}
+		if tm.m.page != paymentPage {
+			t.Errorf("got page %d, want the payment page once the rate is set", tm.m.page)
+		}
+	})
+}
@@ -145,11 +145,12 @@ func (m model) ShopUpdate(msg tea.Msg) (model, tea.Cmd) {
 				} else {
 					if m.anonymous {
 						m.error = &VisibleError{
-							message: "ssh public key required to subscribe, see trm.sh/faq",
+							message: "ssh public key required to subscribe, save your cart from the account page",
 						}
 						return m, nil
 					}
 					m.state.subscribe.product = &product
+					m.state.subscribe.tax = nil
 					return m.SubscribeSwitch()
 				}
 			}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+// spendingState holds every order the customer placed, which the dashboard
+// sums up. They're paged in the first time it's shown rather than with the
+// rest of the account.
+type spendingState struct {
+	orders  []terminal.Order
+	loading bool
+	loaded  bool
+}
+
+type SpendingOrdersMsg struct {
+	orders []terminal.Order
+}
+
+// sparks are the levels of a sparkline, lowest first.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// spendingMonths is how many months of history the dashboard charts.
+const spendingMonths = 12
+
+// loadSpending loads every order for the dashboard, unless they're loaded or
+// on their way.
+func (m model) loadSpending() (model, tea.Cmd) {
+	if m.state.spending.loaded || m.state.spending.loading {
+		return m, nil
+	}
+	m.state.spending.loading = true
+	return m, m.traced("Order.ListAll", func(ctx context.Context) tea.Msg {
+		orders, err := api.ListAllOrders(ctx, m.client)
+		if err != nil {
+			return err
+		}
+		return SpendingOrdersMsg{orders: orders}
+	})
+}
+
+func sparkline(totals []api.SpendTotal) string {
+	var most int64
+	for _, total := range totals {
+		most = max(most, total.Amount)
+	}
+	line := strings.Builder{}
+	for _, total := range totals {
+		level := 0
+		if most > 0 {
+			level = int(total.Amount * int64(len(sparks)-1) / most)
+		}
+		line.WriteRune(sparks[level])
+	}
+	return line.String()
+}
+
+// productName names the product with id, or the product of the variant
+// with id.
+func (m model) productName(id string) string {
+	for _, product := range m.products {
+		if product.ID == id {
+			return product.Name
+		}
+		for _, variant := range product.Variants {
+			if variant.ID == id {
+				return product.Name
+			}
+		}
+	}
+	return "unknown product"
+}
+
+// barChart draws a bar for each total, labelled with name(total.Key), the
+// longest bar for the largest total.
+func (m model) barChart(totals []api.SpendTotal, name func(string) string, totalWidth int) []string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+
+	labelWidth := 0
+	var most int64
+	for _, total := range totals {
+		labelWidth = max(labelWidth, lipgloss.Width(name(total.Key)))
+		most = max(most, total.Amount)
+	}
+	labelWidth = min(labelWidth, totalWidth/3)
+	barWidth := max(totalWidth-labelWidth-12, 1)
+
+	lines := []string{}
+	for _, total := range totals {
+		label := name(total.Key)
+		if runes := []rune(label); len(runes) > labelWidth {
+			label = string(runes[:max(labelWidth-1, 0)]) + "…"
+		}
+		length := 0
+		if most > 0 {
+			length = int(total.Amount * int64(barWidth) / most)
+		}
+		lines = append(lines, m.theme.Base().Width(labelWidth+1).Render(label)+
+			accent(strings.Repeat("█", length))+
+			base(" "+formatUSD(int(total.Amount))))
+	}
+	return lines
+}
+
+func (m model) SpendingView(totalWidth int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+	highlight := m.theme.TextBrand().Render
+
+	if !m.state.spending.loaded {
+		return base("loading orders...")
+	}
+
+	spending := api.NewSpending(m.state.spending.orders, m.products)
+	prices := map[string]int64{}
+	for _, product := range m.products {
+		for _, variant := range product.Variants {
+			prices[variant.ID] = variant.Price
+		}
+	}
+	monthly := api.MonthlySubscriptionSpend(m.subscriptions, prices)
+
+	lines := []string{
+		base("spent ") + highlight(formatUSD(int(spending.Total))) + base(" over "+pluralize(spending.Orders, "order")),
+	}
+	if len(m.subscriptions) > 0 {
+		lines = append(lines, base("subscriptions: about ")+highlight(formatUSD(int(monthly)))+base(" a month"))
+	}
+	if spending.Orders == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	months := spending.Months
+	if len(months) > spendingMonths {
+		months = months[len(months)-spendingMonths:]
+	}
+	if len(months) > 0 {
+		lines = append(lines, "",
+			accent("by month"),
+			accent(sparkline(months))+base("  "+months[0].Key+" to "+months[len(months)-1].Key),
+		)
+		lines = append(lines, m.barChart(months, func(key string) string { return key }, totalWidth)...)
+	}
+
+	lines = append(lines, "", accent("by product"))
+	lines = append(lines, m.barChart(spending.Products, m.productName, totalWidth)...)
+
+	lines = append(lines, "", accent("by address"))
+	lines = append(lines, m.barChart(spending.Addresses, func(key string) string { return key }, totalWidth)...)
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(count) + " " + noun + "s"
+}
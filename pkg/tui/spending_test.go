@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpendingLoadsEveryOrder(t *testing.T) {
+	order := func(id, subtotal string) string {
+		return `{"id": "` + id + `", "created": "2026-09-01T10:00:00Z", "amount": {"subtotal": ` + subtotal + `, "shipping": 0}}`
+	}
+	tm := newTestModel(t, map[string]string{
+		"GET /order?limit=100":           `{"data": [` + order("ord_3", "3000") + `, ` + order("ord_2", "2000") + `], "next": "c2"}`,
+		"GET /order?cursor=c2&limit=100": `{"data": [` + order("ord_1", "1000") + `]}`,
+	})
+	tm.switchTo(tm.m.AccountSwitch())
+
+	if tm.m.accountPages[tm.m.state.account.selected+1] != spendingPage {
+		t.Fatal("spending isn't the next account page")
+	}
+	tm.press("down")
+	view := tm.m.SpendingView(80)
+	if !strings.Contains(view, "spent $60.00 over 3 orders") {
+		t.Errorf("got\n%s", view)
+	}
+
+	tm.press("up", "down")
+	// the orders page's first page, then the dashboard's two only once
+	loads := tm.api.count("GET", "/order")
+	if loads != 3 {
+		t.Errorf("got %d order list requests, want 3", loads)
+	}
+}
@@ -1,40 +1,26 @@
 package tui
 
 import (
+	"context"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
-	"github.com/terminaldotshop/terminal-sdk-go/option"
 	"github.com/terminaldotshop/terminal/go/pkg/api"
-	"github.com/terminaldotshop/terminal/go/pkg/resource"
 )
 
 // CreateSDKClient creates a Terminal SDK client with the given context, token, and region
 func (m model) CreateSDKClient() *terminal.Client {
-	options := []option.RequestOption{
-		option.WithBaseURL(resource.Resource.Api.Url),
-		option.WithBearerToken(m.accessToken),
-		option.WithAppID("ssh"),
-	}
-
 	// Only add client IP header if in context
-	// Region lookup will be performed server-side
 	clientIP, _ := m.context.Value("client_ip").(*string)
-	if clientIP != nil {
-		options = append(options, option.WithHeader("x-terminal-ip", *clientIP))
-	}
-	if m.region != nil {
-		options = append(options, option.WithHeader("x-terminal-region", string(*m.region)))
-	}
-
-	return terminal.NewClient(options...)
+	return api.NewClient(m.accessToken, clientIP, m.region)
 }
 
 type SplashState struct {
-	data  bool
-	delay bool
+	data      bool
+	delay     bool
+	dismissed bool
 }
 
 type UserSignedInMsg struct {
@@ -52,13 +38,18 @@ func (m model) LoadCmds() []tea.Cmd {
 		return DelayCompleteMsg{}
 	}))
 
-	cmds = append(cmds, func() tea.Msg {
-		response, err := m.client.View.Init(m.context)
+	cmds = append(cmds, m.traced("View.Init", func(ctx context.Context) tea.Msg {
+		response, err := m.client.View.Init(ctx, api.WithoutOrders())
 		if err != nil {
 			return err
 		}
 		return response.Data
-	})
+	}))
+
+	cmds = append(cmds, m.LoadCartCodesCmd())
+	if !m.anonymous {
+		cmds = append(cmds, m.LoadKeysCmd())
+	}
 
 	return cmds
 }
@@ -69,8 +60,8 @@ func (m model) IsLoadingComplete() bool {
 }
 
 func (m model) SplashInit() tea.Cmd {
-	cmd := func() tea.Msg {
-		token, err := api.FetchUserToken(m.fingerprint)
+	cmd := m.traced("User.SignIn", func(ctx context.Context) tea.Msg {
+		token, err := api.FetchUserToken(ctx, m.fingerprint, m.legacyFingerprint)
 		if err != nil {
 			return err
 		}
@@ -84,7 +75,7 @@ func (m model) SplashInit() tea.Cmd {
 			accessToken: token.AccessToken,
 			client:      client,
 		}
-	}
+	})
 
 	disableMouseCmd := func() tea.Msg {
 		return tea.DisableMouse()
@@ -103,14 +94,50 @@ func (m model) SplashUpdate(msg tea.Msg) (model, tea.Cmd) {
 		m.state.splash.delay = true
 	case terminal.ViewInitResponseData:
 		m.state.splash.data = true
+	case tea.KeyMsg:
+		if msg.String() == "enter" && m.showMaintenanceSplash() {
+			m.state.splash.dismissed = true
+		}
 	}
 
-	if m.IsLoadingComplete() {
+	if m.IsLoadingComplete() && !m.showMaintenanceSplash() {
 		return m.InitialDataLoaded()
 	}
 	return m, nil
 }
 
+// showMaintenanceSplash holds the session on a full-screen maintenance notice
+// until it's dismissed, when an operator asks for one.
+func (m model) showMaintenanceSplash() bool {
+	return m.status.Maintenance && m.status.Splash && !m.state.splash.dismissed
+}
+
+func (m model) MaintenanceSplashView() string {
+	width := min(m.viewportWidth-4, 50)
+	hint := m.theme.TextBrand().Render("press enter to browse the shop")
+	if !m.IsLoadingComplete() {
+		hint = m.theme.Base().Render("loading...")
+	}
+
+	return lipgloss.Place(
+		m.viewportWidth,
+		m.viewportHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		lipgloss.JoinVertical(
+			lipgloss.Center,
+			m.LogoView(),
+			"",
+			"",
+			m.theme.TextAccent().Bold(true).Render("down for maintenance"),
+			"",
+			m.theme.Base().Width(width).Align(lipgloss.Center).Render(m.maintenanceNotice()),
+			"",
+			hint,
+		),
+	)
+}
+
 func (m model) SplashView() string {
 	var msg string
 	if m.error != nil {
@@ -131,6 +158,10 @@ func (m model) SplashView() string {
 		hint = ""
 	}
 
+	if m.error == nil && m.showMaintenanceSplash() {
+		return m.MaintenanceSplashView()
+	}
+
 	if m.error == nil {
 		return lipgloss.Place(
 			m.viewportWidth,
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/terminaldotshop/terminal/go/pkg/recording"
+	"github.com/terminaldotshop/terminal/go/pkg/sessions"
+)
+
+var pageNames = map[page]string{
+	menuPage:          "menu",
+	splashPage:        "splash",
+	shopPage:          "shop",
+	accountPage:       "account",
+	paymentPage:       "payment",
+	cartPage:          "cart",
+	subscribePage:     "subscribe",
+	shippingPage:      "shipping",
+	confirmPage:       "confirm",
+	finalSubPage:      "final subscription",
+	finalPage:         "final",
+	subscriptionsPage: "subscriptions",
+	tokensPage:        "tokens",
+	appsPage:          "apps",
+	ordersPage:        "orders",
+	aboutPage:         "about",
+	faqPage:           "faq",
+	keysPage:          "keys",
+	claimPage:         "claim",
+	spendingPage:      "spending",
+	supportPage:       "support",
+}
+
+func (m model) StatusUpdate(status sessions.Status) model {
+	m.status = status
+	m.heightContent = m.heightContainer - lipgloss.Height(m.HeaderView()) - lipgloss.Height(m.FooterView()) - lipgloss.Height(m.BreadcrumbsView())
+	return m
+}
+
+// maintenanceNotice explains why checkout is paused.
+func (m model) maintenanceNotice() string {
+	if m.status.Reason != "" {
+		return "checkout is paused for maintenance: " + m.status.Reason
+	}
+	return "checkout is paused for maintenance, your cart is saved for later"
+}
+
+// BannerView is the maintenance notice, the operator's message and whether
+// the session is being recorded, shown under the header on every page until
+// they're cleared.
+func (m model) BannerView() string {
+	lines := []string{}
+	if recording.FromContext(m.context) != nil {
+		lines = append(lines, "● this session is being recorded for support, as you asked")
+	}
+	if m.status.Maintenance {
+		lines = append(lines, m.maintenanceNotice())
+	}
+	if m.status.Banner != "" {
+		lines = append(lines, m.status.Banner)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	text := strings.Join(lines, "\n")
+
+	return m.theme.Base().
+		Background(m.theme.Brand()).
+		Foreground(m.theme.Background()).
+		Bold(true).
+		Width(m.widthContainer).
+		Padding(0, 1).
+		Render(wordWrap(text, m.widthContainer-2))
+}
@@ -6,12 +6,15 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type subscribeState struct {
 	product      *terminal.Product
 	selected     int
 	lastUpdateID int64
+	// tax is estimated for the address once it's chosen
+	tax *api.Tax
 }
 
 func (m model) VisibleSubscribeItems() []terminal.ProductVariant {
@@ -1,16 +1,19 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
 )
 
 type subscriptionsState struct {
 	selected int
 	deleting *int
+	removing string
 }
 
 func (m model) SubscriptionManageSwitch(id string) (model, tea.Cmd) {
@@ -81,27 +84,26 @@ func (m model) SubscriptionsUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.previousSubscription()
 			}
 		case "delete", "d", "backspace", "x":
-			if m.state.subscriptions.deleting == nil {
+			if m.state.subscriptions.deleting == nil && m.state.subscriptions.removing == "" {
 				m.state.subscriptions.deleting = &m.state.subscriptions.selected
 			}
 			return m, nil
 		case "y":
 			if m.state.subscriptions.deleting != nil {
 				m.state.subscriptions.deleting = nil
-				_, err := m.client.Subscription.Delete(m.context, m.subscriptions[m.state.subscriptions.selected].ID)
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-				if len(m.subscriptions)-1 == 0 {
-					m.state.account.focused = false
-				}
-				return m, func() tea.Msg {
-					subscriptions, err := m.client.Subscription.List(m.context)
+				id := m.subscriptions[m.state.subscriptions.selected].ID
+				m.state.subscriptions.removing = id
+				return m, m.traced("Subscription.Delete", func(ctx context.Context) tea.Msg {
+					_, err := m.client.Subscription.Delete(ctx, id)
+					if err != nil {
+						return err
+					}
+					subscriptions, err := m.client.Subscription.List(ctx)
 					if err != nil {
 						return err
 					}
 					return subscriptions.Data
-				}
+				})
 			}
 			return m, nil
 		case "n", "esc":
@@ -143,7 +145,7 @@ func (m model) formatSubscription(subscription terminal.Subscription, totalWidth
 		title = accent(title) + base(fmt.Sprintf(" (every %d %s)", subscription.Schedule.Interval, scheduleType))
 	}
 
-	price := fmt.Sprintf(" $%2v", subscription.Quantity*variant.Price/100)
+	price := " " + formatUSD(int(subscription.Quantity*variant.Price))
 	space := totalWidth - lipgloss.Width(
 		title,
 	) - lipgloss.Width(price) - 2
@@ -157,6 +159,9 @@ func (m model) formatSubscription(subscription terminal.Subscription, totalWidth
 	lines := []string{}
 	lines = append(lines, content)
 	lines = append(lines, fmt.Sprintf("next shipment: %s", subscription.Next))
+	if tax := api.SubscriptionTax(subscription); tax != nil {
+		lines = append(lines, fmt.Sprintf("plus %s: %s", tax.Label(), formatUSD(int(tax.Amount))))
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -171,6 +176,9 @@ func (m model) SubscriptionsView(totalWidth int, focused bool) string {
 		if m.state.subscriptions.deleting != nil && *m.state.subscriptions.deleting == i {
 			content = accent("are you sure?") + base("\n(y/n)")
 		}
+		if subscription.ID == m.state.subscriptions.removing {
+			content = base("cancelling...")
+		}
 		box := m.CreateBoxCustom(
 			content,
 			focused && i == m.state.subscriptions.selected,
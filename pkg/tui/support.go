@@ -0,0 +1,499 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/logger"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+// supportPollInterval is how often an open conversation checks for replies.
+const supportPollInterval = 5 * time.Second
+
+type supportState struct {
+	// selected is the ticket in the list, or opening another one past the
+	// end of it.
+	selected int
+	loading  bool
+	// opening shows the form for a new ticket and viewing the conversation
+	// of ticket, which replying adds to.
+	opening  bool
+	viewing  bool
+	replying bool
+	sending  bool
+	ticket   *api.Ticket
+	input    ticketInput
+	reply    string
+	form     *huh.Form
+	// poll is the generation of the conversation's poll for replies, bumped
+	// to stop it.
+	poll int
+	// orders are the customer's most recent, which a new ticket can be
+	// about.
+	orders []terminal.Order
+}
+
+type ticketInput struct {
+	subject string
+	orderID string
+	message string
+}
+
+type SupportOrdersMsg struct {
+	orders []terminal.Order
+}
+
+type TicketMsg struct {
+	ticket api.Ticket
+}
+
+// SupportPollMsg carries the poll it belongs to, so one that's been
+// abandoned by leaving the conversation stops at its next tick. ticket is
+// nil when the check failed.
+type SupportPollMsg struct {
+	poll   int
+	ticket *api.Ticket
+}
+
+var supportCommands = []footerCommand{
+	{key: "↑/↓", value: "navigate"},
+	{key: "enter", value: "open"},
+	{key: "esc", value: "back"},
+}
+
+func ticketCommands(ticket api.Ticket) []footerCommand {
+	commands := []footerCommand{
+		{key: "esc", value: "back to tickets"},
+		{key: "↑/↓", value: "scroll"},
+	}
+	if !ticket.Closed() {
+		commands = append(commands, footerCommand{key: "r", value: "reply"})
+	}
+	return commands
+}
+
+// loadTickets loads the customer's tickets, and their recent orders for a
+// new one. Without the orders a ticket can still be opened, so their failure
+// is only logged.
+func (m model) loadTickets() (model, tea.Cmd) {
+	m.state.support.loading = true
+	return m, tea.Batch(
+		m.traced("Support.List", func(ctx context.Context) tea.Msg {
+			tickets, err := api.ListTickets(ctx, m.client)
+			if err != nil {
+				return err
+			}
+			return tickets
+		}),
+		m.traced("Order.List", func(ctx context.Context) tea.Msg {
+			page, err := api.ListOrders(ctx, m.client, api.OrderQuery{Limit: ordersPageSize})
+			if err != nil {
+				logger.FromContext(ctx).Warn("could not load orders for support", "error", err)
+				return nil
+			}
+			return SupportOrdersMsg{orders: page.Orders}
+		}),
+	)
+}
+
+// ticketUpdated swaps the new copy of a ticket in wherever it's shown.
+func (m model) ticketUpdated(ticket api.Ticket) model {
+	m.state.support.sending = false
+	found := false
+	for i := range m.tickets {
+		if m.tickets[i].ID == ticket.ID {
+			m.tickets[i] = ticket
+			found = true
+		}
+	}
+	if !found {
+		m.tickets = append([]api.Ticket{ticket}, m.tickets...)
+	}
+	if m.state.support.ticket != nil && m.state.support.ticket.ID == ticket.ID {
+		m.state.support.ticket = &ticket
+	}
+	return m
+}
+
+// pollTicket checks the conversation being viewed for replies a while from
+// now. A failed check is only logged, the next one may well succeed.
+func (m model) pollTicket(poll int) tea.Cmd {
+	ticketID := m.state.support.ticket.ID
+	check := m.traced("Support.Get", func(ctx context.Context) tea.Msg {
+		ticket, err := api.GetTicket(ctx, m.client, ticketID)
+		if err != nil {
+			logger.FromContext(ctx).Warn("support ticket check failed", "error", err)
+			return SupportPollMsg{poll: poll}
+		}
+		return SupportPollMsg{poll: poll, ticket: &ticket}
+	})
+	return tea.Tick(supportPollInterval, func(t time.Time) tea.Msg {
+		if m.context.Err() != nil {
+			return nil
+		}
+		return check()
+	})
+}
+
+// TicketSwitch opens the conversation of ticket, fetching its messages
+// and polling for replies while it's open.
+func (m model) TicketSwitch(ticket api.Ticket) (model, tea.Cmd) {
+	m.state.support.viewing = true
+	m.state.support.opening = false
+	m.state.support.replying = false
+	m.state.support.ticket = &ticket
+	m.state.support.poll++
+	m.state.footer.commands = ticketCommands(ticket)
+
+	poll := m.state.support.poll
+	ticketID := ticket.ID
+	return m, tea.Batch(
+		m.traced("Support.Get", func(ctx context.Context) tea.Msg {
+			ticket, err := api.GetTicket(ctx, m.client, ticketID)
+			if err != nil {
+				return err
+			}
+			return TicketMsg{ticket: ticket}
+		}),
+		m.pollTicket(poll),
+	)
+}
+
+// leaveTicket goes back to the list of tickets, stopping the poll.
+func (m model) leaveTicket() model {
+	m.state.support.viewing = false
+	m.state.support.opening = false
+	m.state.support.replying = false
+	m.state.support.poll++
+	m.state.footer.commands = supportCommands
+	return m
+}
+
+func (m model) NewTicketSwitch() (model, tea.Cmd) {
+	m.state.support.opening = true
+	m.state.support.input = ticketInput{}
+
+	orders := []huh.Option[string]{huh.NewOption("no order", "")}
+	for _, order := range m.state.support.orders {
+		name := fmt.Sprintf("order #%d · %s", order.Index, m.formatDate(order.Created))
+		orders = append(orders, huh.NewOption(name, order.ID))
+	}
+
+	m.state.support.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("what's it about?").
+				Key("subject").
+				CharLimit(100).
+				Value(&m.state.support.input.subject).
+				Validate(validate.Compose(
+					validate.NotEmpty("subject"),
+					validate.WithinLen(0, 100, "subject"),
+				)),
+			huh.NewSelect[string]().
+				Title("which order?").
+				Key("order").
+				Options(orders...).
+				Value(&m.state.support.input.orderID),
+			huh.NewText().
+				Title("message").
+				Key("message").
+				CharLimit(2000).
+				Value(&m.state.support.input.message).
+				Validate(validate.Compose(
+					validate.NotEmpty("message"),
+					validate.WithinLen(0, 2000, "message"),
+				)),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "next"},
+	}
+	return m, m.state.support.form.Init()
+}
+
+func (m model) ReplySwitch() (model, tea.Cmd) {
+	m.state.support.replying = true
+	m.state.support.reply = ""
+	m.state.support.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("reply").
+				Key("reply").
+				CharLimit(2000).
+				Value(&m.state.support.reply).
+				Validate(validate.Compose(
+					validate.NotEmpty("reply"),
+					validate.WithinLen(0, 2000, "reply"),
+				)),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "send"},
+	}
+	return m, m.state.support.form.Init()
+}
+
+func (m model) supportFormUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" && !m.state.support.sending {
+			if m.state.support.replying {
+				m.state.support.replying = false
+				m.state.footer.commands = ticketCommands(*m.state.support.ticket)
+				return m, nil
+			}
+			return m.leaveTicket(), nil
+		}
+	}
+
+	next, cmd := m.state.support.form.Update(msg)
+	m.state.support.form = next.(*huh.Form)
+	if m.state.support.sending || m.state.support.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.state.support.sending = true
+	form := m.state.support.form
+	if m.state.support.replying {
+		ticketID := m.state.support.ticket.ID
+		m.state.support.reply = form.GetString("reply")
+		body := strings.TrimSpace(m.state.support.reply)
+		m.state.support.replying = false
+		m.state.footer.commands = ticketCommands(*m.state.support.ticket)
+		return m, m.traced("Support.Reply", func(ctx context.Context) tea.Msg {
+			ticket, err := api.ReplyTicket(ctx, m.client, ticketID, body)
+			if err != nil {
+				return err
+			}
+			return TicketMsg{ticket: ticket}
+		})
+	}
+
+	input := ticketInput{
+		subject: form.GetString("subject"),
+		orderID: form.GetString("order"),
+		message: form.GetString("message"),
+	}
+	m.state.support.input = input
+	params := api.TicketParams{
+		Subject: strings.TrimSpace(input.subject),
+		OrderID: input.orderID,
+		Message: strings.TrimSpace(input.message),
+	}
+	return m, m.traced("Support.New", func(ctx context.Context) tea.Msg {
+		ticket, err := api.NewTicket(ctx, m.client, params)
+		if err != nil {
+			return err
+		}
+		return TicketMsg{ticket: ticket}
+	})
+}
+
+func (m model) SupportUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TicketMsg:
+		// applied here rather than in the root update, so the account page
+		// sees the messages change and scrolls to the newest
+		m = m.ticketUpdated(msg.ticket)
+		if m.state.support.opening {
+			return m.TicketSwitch(msg.ticket)
+		}
+		return m, nil
+	case SupportPollMsg:
+		if msg.poll != m.state.support.poll || !m.state.support.viewing {
+			return m, nil
+		}
+		if msg.ticket != nil {
+			m = m.ticketUpdated(*msg.ticket)
+			if msg.ticket.Closed() {
+				m.state.support.replying = false
+				m.state.footer.commands = ticketCommands(*msg.ticket)
+				return m, nil
+			}
+		}
+		return m, m.pollTicket(msg.poll)
+	}
+
+	if m.state.support.opening || m.state.support.replying {
+		return m.supportFormUpdate(msg)
+	}
+
+	if m.state.support.viewing {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc", "q", "backspace":
+				return m.leaveTicket(), nil
+			case "r":
+				if !m.state.support.sending && !m.state.support.ticket.Closed() {
+					return m.ReplySwitch()
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.state.account.detailViewport.KeyMap = viewport.DefaultKeyMap()
+			m.state.account.detailViewport, cmd = m.state.account.detailViewport.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	m.state.footer.commands = supportCommands
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down", "tab":
+			m.state.support.selected = min(m.state.support.selected+1, len(m.tickets))
+		case "k", "up", "shift+tab":
+			m.state.support.selected = max(m.state.support.selected-1, 0)
+		case "enter":
+			if m.state.support.loading {
+				return m, nil
+			}
+			if m.state.support.selected == len(m.tickets) {
+				return m.NewTicketSwitch()
+			}
+			return m.TicketSwitch(m.tickets[m.state.support.selected])
+		}
+	}
+	return m, nil
+}
+
+// supportMessages counts the messages of the conversation being viewed, -1
+// when there's none, to tell when to scroll to the newest.
+func (m model) supportMessages() int {
+	if !m.state.support.viewing || m.state.support.ticket == nil {
+		return -1
+	}
+	return len(m.state.support.ticket.Messages)
+}
+
+func (m model) formatTicket(ticket api.Ticket) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+	highlight := m.theme.TextBrand().Render
+
+	status := ticket.Status
+	if ticket.Awaiting() {
+		status = "waiting for the shop"
+	} else if !ticket.Closed() && len(ticket.Messages) > 0 {
+		status = highlight("replied")
+	}
+
+	lines := []string{accent(ticket.Subject) + base("  ") + base(status)}
+	if ticket.OrderID != "" {
+		lines = append(lines, base("order: "+m.ticketOrderName(ticket.OrderID)))
+	}
+	lines = append(lines, base("updated: "+m.formatDate(ticket.Updated)))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// ticketOrderName names the order a ticket is about by its number, when
+// it's one of the user's recent orders.
+func (m model) ticketOrderName(orderID string) string {
+	for _, order := range m.state.support.orders {
+		if order.ID == orderID {
+			return fmt.Sprintf("#%d", order.Index)
+		}
+	}
+	return orderID
+}
+
+// ticketView lays out a conversation like a chat, the shop's messages on the
+// left and the customer's on the right.
+func (m model) ticketView(ticket api.Ticket, totalWidth int) string {
+	base := m.theme.Base().Render
+	accent := m.theme.TextAccent().Render
+	highlight := m.theme.TextBrand().Render
+
+	lines := []string{base("< ") + accent("esc ") + base("back to tickets\n")}
+	lines = append(lines, highlight(ticket.Subject))
+	if ticket.OrderID != "" {
+		lines = append(lines, base("order: "+m.ticketOrderName(ticket.OrderID)))
+	}
+	lines = append(lines, "")
+
+	bubbleWidth := max(totalWidth*3/4, 10)
+	for _, message := range ticket.Messages {
+		author, align := "terminal", lipgloss.Left
+		if message.Author == api.AuthorCustomer {
+			author, align = "you", lipgloss.Right
+		}
+		header := accent(author) + base(" · "+m.formatDate(message.Created))
+		if message.Author == api.AuthorShop {
+			header = highlight(author) + base(" · "+m.formatDate(message.Created))
+		}
+		bubble := lipgloss.JoinVertical(align,
+			header,
+			base(wordWrap(message.Body, bubbleWidth)),
+		)
+		lines = append(lines, m.theme.Base().Width(totalWidth).Align(align).Render(bubble), "")
+	}
+
+	switch {
+	case m.state.support.sending:
+		lines = append(lines, accent("sending..."))
+	case m.state.support.replying:
+		lines = append(lines, m.state.support.form.WithWidth(totalWidth).View())
+	case ticket.Closed():
+		lines = append(lines, base("this ticket is closed, open another if you still need help"))
+	case ticket.Awaiting():
+		lines = append(lines, base(wordWrap("we'll reply here, usually within a day. replies show up while this is open.", totalWidth)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (m model) SupportView(totalWidth int, focused bool) string {
+	base := m.theme.Base().Render
+
+	if m.state.support.viewing && m.state.support.ticket != nil {
+		return m.ticketView(*m.state.support.ticket, totalWidth)
+	}
+	if m.state.support.opening {
+		if m.state.support.sending {
+			return base("opening ticket...")
+		}
+		return m.state.support.form.WithWidth(totalWidth).View()
+	}
+	if m.state.support.loading && len(m.tickets) == 0 {
+		return base("loading tickets...")
+	}
+
+	tickets := []string{}
+	for i, ticket := range m.tickets {
+		tickets = append(tickets, m.CreateBoxCustom(
+			m.formatTicket(ticket),
+			focused && i == m.state.support.selected,
+			totalWidth,
+		))
+	}
+
+	newIndex := len(m.tickets)
+	tickets = append(tickets, m.CreateBoxCustom(
+		m.formatListItemCustom("open a ticket", m.state.support.selected == newIndex, totalWidth, false),
+		focused && m.state.support.selected == newIndex,
+		totalWidth,
+	))
+
+	return m.theme.Base().Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		tickets...,
+	))
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+)
+
+const testTicket = `{"id": "tkt_1", "subject": "my order", "orderID": "ord_1", "status": "open",
+	"messages": [{"id": "msg_1", "author": "customer", "body": "is it lost? quite late"}]}`
+
+// supportTestModel opens the support page of the account, where the customer
+// has testTicket.
+func supportTestModel(t *testing.T) *testModel {
+	t.Helper()
+	tm := newTestModel(t, map[string]string{
+		"GET /order?limit=20":                `{"data": [{"id": "ord_1", "index": 7, "created": "2026-10-01T12:00:00Z"}]}`,
+		"GET /support/ticket":                `{"data": [` + testTicket + `]}`,
+		"POST /support/ticket":               `{"data": ` + testTicket + `}`,
+		"GET /support/ticket/tkt_1":          `{"data": ` + testTicket + `}`,
+		"POST /support/ticket/tkt_1/message": `{"data": ` + testTicket + `}`,
+	})
+	tm.switchTo(tm.m.AccountSwitch())
+	tm.m.state.account.selected = slices.Index(tm.m.accountPages, supportPage)
+	tm.press("enter")
+	if len(tm.m.tickets) != 1 || len(tm.m.state.support.orders) != 1 {
+		t.Fatalf("got %d tickets and %d orders", len(tm.m.tickets), len(tm.m.state.support.orders))
+	}
+	return tm
+}
+
+func TestNewTicket(t *testing.T) {
+	tm := supportTestModel(t)
+	tm.press("down", "enter")
+	if !tm.m.state.support.opening {
+		t.Fatal("the new ticket form didn't open")
+	}
+
+	tm.press("my order", "enter", "down", "enter", "is it lost? quite late")
+	if tm.m.page != accountPage || tm.quit || !tm.m.state.support.opening {
+		t.Fatalf("typing the ticket left the form for page %d", tm.m.page)
+	}
+	tm.press("enter")
+
+	request, ok := tm.api.request("POST", "/support/ticket")
+	if !ok {
+		t.Fatal("the ticket wasn't opened")
+	}
+	params := api.TicketParams{}
+	if err := json.Unmarshal([]byte(request.body), &params); err != nil {
+		t.Fatal(err)
+	}
+	want := api.TicketParams{Subject: "my order", OrderID: "ord_1", Message: "is it lost? quite late"}
+	if params != want {
+		t.Errorf("got %+v, want %+v", params, want)
+	}
+	if !tm.m.state.support.viewing || tm.m.state.support.ticket.ID != "tkt_1" {
+		t.Error("the new ticket's conversation didn't open")
+	}
+}
+
+func TestTicketReply(t *testing.T) {
+	tm := supportTestModel(t)
+	tm.press("enter")
+	if !tm.m.state.support.viewing {
+		t.Fatal("the conversation didn't open")
+	}
+	if got := tm.m.ticketOrderName("ord_1"); got != "#7" {
+		t.Errorf("got order %q, want #7", got)
+	}
+
+	tm.press("r", "any news? still waiting")
+	if tm.m.page != accountPage || tm.quit || !tm.m.state.support.replying {
+		t.Fatalf("typing the reply left the form for page %d", tm.m.page)
+	}
+	tm.press("enter")
+
+	request, ok := tm.api.request("POST", "/support/ticket/tkt_1/message")
+	if !ok {
+		t.Fatal("the reply wasn't sent")
+	}
+	if want := `{"body":"any news? still waiting"}`; request.body != want {
+		t.Errorf("got %s, want %s", request.body, want)
+	}
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	terminal "github.com/terminaldotshop/terminal-sdk-go"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/tui/validate"
+)
+
+// vatState is the VAT ID form on the confirm page, for business buyers who
+// need an invoice. The ID itself is kept by the API on the cart.
+type vatState struct {
+	editing bool
+	saving  bool
+	input   string
+	form    *huh.Form
+}
+
+type VATIDSavedMsg struct {
+	cart terminal.Cart
+}
+
+// Tax is the tax on the order being checked out, or nil until the API has
+// worked it out for an address.
+func (m model) Tax() *api.Tax {
+	if m.IsSubscribing() {
+		return m.state.subscribe.tax
+	}
+	return api.CartTax(m.cart)
+}
+
+// taxAmount is what tax adds to a total, nothing if there's none.
+func taxAmount(tax *api.Tax) int64 {
+	if tax == nil {
+		return 0
+	}
+	return tax.Amount
+}
+
+// canSetVATID is true when the order is charged VAT, which business buyers
+// can have invoiced to their VAT ID.
+func (m model) canSetVATID() bool {
+	tax := m.Tax()
+	return !m.IsSubscribing() && tax != nil && tax.Kind == api.TaxVAT
+}
+
+func (m model) VATIDSwitch() (model, tea.Cmd) {
+	m.state.vat.editing = true
+	m.state.vat.input = ""
+	if tax := m.Tax(); tax != nil {
+		m.state.vat.input = tax.VATID
+	}
+	m.state.vat.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("vat id").
+				Description("for business purchases, leave empty to remove").
+				Key("vatID").
+				Value(&m.state.vat.input).
+				Validate(validate.IsVATID("vat id")),
+		),
+	).
+		WithTheme(m.theme.Form()).
+		WithWidth(m.widthContent - 2).
+		WithShowHelp(false)
+	m.state.footer.commands = []footerCommand{
+		{key: "esc", value: "cancel"},
+		{key: "enter", value: "save"},
+	}
+	return m, m.state.vat.form.Init()
+}
+
+func (m model) VATIDUpdate(msg tea.Msg) (model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.state.vat.editing = false
+			return m.ConfirmSwitch()
+		}
+	}
+
+	next, cmd := m.state.vat.form.Update(msg)
+	m.state.vat.form = next.(*huh.Form)
+	if !m.state.vat.saving && m.state.vat.form.State == huh.StateCompleted {
+		m.state.vat.saving = true
+		m.state.vat.input = m.state.vat.form.GetString("vatID")
+		vatID := strings.ToUpper(strings.ReplaceAll(m.state.vat.input, " ", ""))
+		return m, m.traced("Cart.SetVATID", func(ctx context.Context) tea.Msg {
+			if err := api.SetCartVATID(ctx, m.client, vatID); err != nil {
+				return err
+			}
+			cart, err := m.client.Cart.Get(ctx)
+			if err != nil {
+				return err
+			}
+			return VATIDSavedMsg{cart: cart.Data}
+		})
+	}
+	return m, cmd
+}
+
+func (m model) VATIDView() string {
+	if m.state.vat.saving {
+		return m.theme.Base().Width(m.widthContent).Render(" saving vat id...")
+	}
+	return m.theme.Base().Padding(0, 1).Render(m.state.vat.form.View())
+}
+
+// formatInvoice breaks an order's price down the way an invoice to a VAT
+// registered business needs it. net is the price before tax.
+func formatInvoice(tax api.Tax, net int64) []string {
+	lines := []string{
+		"vat id: " + tax.VATID,
+		fmt.Sprintf("%-10s %s", "net:", formatUSD(int(net))),
+		fmt.Sprintf("%-10s %s", tax.Label()+":", formatUSD(int(tax.Amount))),
+		fmt.Sprintf("%-10s %s", "gross:", formatUSD(int(net+tax.Amount))),
+	}
+	if tax.ReverseCharge {
+		lines = append(lines, "reverse charge: vat to be accounted for by the buyer")
+	}
+	return lines
+}
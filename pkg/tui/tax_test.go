@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/terminaldotshop/terminal-sdk-go"
+)
+
+// cartWithTax is a cart shipping to an address the API charges tax for.
+func cartWithTax(t *testing.T, tax string) terminal.Cart {
+	t.Helper()
+	cart := terminal.Cart{}
+	body := `{"items": [{"id": "itm_1", "productVariantID": "var_1", "quantity": 2}],
+		"amount": {"subtotal": 4400, "shipping": 800, "tax": ` + tax + `}}`
+	if err := json.Unmarshal([]byte(body), &cart); err != nil {
+		t.Fatal(err)
+	}
+	return cart
+}
+
+func TestVATIDForm(t *testing.T) {
+	tm := newTestModel(t, map[string]string{
+		"GET /cart": `{"data": {"items": [{"id": "itm_1", "productVariantID": "var_1", "quantity": 2}],
+			"amount": {"subtotal": 4400, "shipping": 800,
+				"tax": {"kind": "vat", "amount": 0, "vatID": "DE123456789", "reverseCharge": true}}}}`,
+	})
+	tm.m.products = []terminal.Product{{ID: "prd_1", Name: "segfault", Variants: []terminal.ProductVariant{{ID: "var_1"}}}}
+	tm.m.cart = cartWithTax(t, `{"kind": "vat", "rate": 0.2, "amount": 1040}`)
+	tm.switchTo(tm.m.ConfirmSwitch())
+
+	tm.press("v")
+	if !tm.m.state.vat.editing {
+		t.Fatal("the vat id form didn't open")
+	}
+	tm.press("de", "enter")
+	if _, ok := tm.api.request("PUT", "/cart/vat"); ok {
+		t.Fatal("saved an invalid vat id")
+	}
+	tm.press("123 456 789", "enter")
+
+	request, ok := tm.api.request("PUT", "/cart/vat")
+	if !ok {
+		t.Fatal("the vat id wasn't saved")
+	}
+	if want := `{"vatID":"DE123456789"}`; request.body != want {
+		t.Errorf("got %s, want %s", request.body, want)
+	}
+	if tm.m.state.vat.editing || tm.m.page != confirmPage {
+		t.Error("the vat id form is still open once saved")
+	}
+	if tax := tm.m.Tax(); tax == nil || !tax.ReverseCharge {
+		t.Errorf("got tax %+v, want the cart's reverse charged vat", tax)
+	}
+}
+
+func TestVATIDOnlyForVAT(t *testing.T) {
+	tm := newTestModel(t, nil)
+	tm.m.products = []terminal.Product{{ID: "prd_1", Name: "segfault", Variants: []terminal.ProductVariant{{ID: "var_1"}}}}
+	tm.m.cart = cartWithTax(t, `{"kind": "sales", "rate": 0.0825, "amount": 429}`)
+	tm.switchTo(tm.m.ConfirmSwitch())
+
+	tm.press("v")
+	if tm.m.state.vat.editing {
+		t.Error("offered a vat id for sales tax")
+	}
+}
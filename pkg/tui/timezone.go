@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"context"
+	"time"
+)
+
+type timezoneKey struct{}
+
+// WithTimezone sets the timezone dates are shown in, by IANA name, e.g.
+// "Europe/Berlin". Dates stay in UTC if the name is empty or unknown.
+func WithTimezone(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, timezoneKey{}, location)
+}
+
+func timezoneFromContext(ctx context.Context) *time.Location {
+	if location, ok := ctx.Value(timezoneKey{}).(*time.Location); ok {
+		return location
+	}
+	return time.UTC
+}
+
+// formatDate shows a timestamp from the API in the customer's timezone, or
+// as it came if it doesn't parse.
+func (m model) formatDate(timestamp string) string {
+	date, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return date.In(m.location).Format("Jan 2, 2006 15:04 MST")
+}
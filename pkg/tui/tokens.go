@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/terminaldotshop/terminal-sdk-go"
@@ -14,6 +16,7 @@ type TokenAddedMsg struct {
 type tokensState struct {
 	selected int
 	deleting *int
+	removing string
 	newToken *terminal.TokenNewResponseData
 }
 
@@ -59,27 +62,26 @@ func (m model) TokensUpdate(msg tea.Msg) (model, tea.Cmd) {
 				return m.previousToken()
 			}
 		case "delete", "d", "backspace", "x":
-			if m.state.tokens.deleting == nil {
+			if m.state.tokens.deleting == nil && m.state.tokens.removing == "" {
 				m.state.tokens.deleting = &m.state.tokens.selected
 			}
 			return m, nil
 		case "y":
 			if m.state.tokens.deleting != nil {
 				m.state.tokens.deleting = nil
-				_, err := m.client.Token.Delete(m.context, m.tokens[m.state.tokens.selected].ID)
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-				if len(m.tokens)-1 == 0 {
-					m.state.account.focused = false
-				}
-				return m, func() tea.Msg {
-					tokens, err := m.client.Token.List(m.context)
+				id := m.tokens[m.state.tokens.selected].ID
+				m.state.tokens.removing = id
+				return m, m.traced("Token.Delete", func(ctx context.Context) tea.Msg {
+					_, err := m.client.Token.Delete(ctx, id)
+					if err != nil {
+						return err
+					}
+					tokens, err := m.client.Token.List(ctx)
 					if err != nil {
 						return err
 					}
 					return tokens.Data
-				}
+				})
 			}
 			return m, nil
 		case "n", "esc":
@@ -87,12 +89,12 @@ func (m model) TokensUpdate(msg tea.Msg) (model, tea.Cmd) {
 			return m, nil
 		case "enter":
 			if m.state.tokens.deleting == nil && m.state.tokens.selected == len(m.tokens) {
-				return m, func() tea.Msg {
-					response, err := m.client.Token.New(m.context)
+				return m, m.traced("Token.New", func(ctx context.Context) tea.Msg {
+					response, err := m.client.Token.New(ctx)
 					if err != nil {
 						return err
 					}
-					tokens, err := m.client.Token.List(m.context)
+					tokens, err := m.client.Token.List(ctx)
 					if err != nil {
 						return err
 					}
@@ -103,7 +105,7 @@ func (m model) TokensUpdate(msg tea.Msg) (model, tea.Cmd) {
 						newToken: response.Data,
 						tokens:   tokens.Data,
 					}
-				}
+				})
 			}
 		}
 	case TokenAddedMsg:
@@ -152,6 +154,9 @@ func (m model) TokensView(totalWidth int, focused bool) string {
 		if m.state.tokens.deleting != nil && *m.state.tokens.deleting == i {
 			content = accent("are you sure you want to revoke?") + base("\n(y/n)")
 		}
+		if token.ID == m.state.tokens.removing {
+			content = base("revoking...")
+		}
 		box := m.CreateBoxCustom(
 			content,
 			focused && i == m.state.tokens.selected,
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/terminaldotshop/terminal/go/pkg/api"
+	"github.com/terminaldotshop/terminal/go/pkg/sessions"
+	"github.com/terminaldotshop/terminal/go/pkg/telemetry"
+)
+
+// requestTimeout bounds each command, retries included, so a hung request
+// can't leave a page waiting forever.
+const requestTimeout = 30 * time.Second
+
+// RetryingMsg reports that a request made by a traced command failed and is
+// being retried, or, once retry is nil, that the command has finished.
+type RetryingMsg struct {
+	retry *api.Retry
+}
+
+// traced returns a command that runs fn inside a span named name, parented to
+// the session span. A returned error message marks the span as failed.
+// Retries of its requests are sent to the session as RetryingMsg. The context
+// given to fn is cancelled after requestTimeout or when the session ends.
+func (m model) traced(name string, fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.context, requestTimeout)
+		defer cancel()
+		ctx, span := telemetry.Start(ctx, name)
+		session := sessions.FromContext(m.context)
+		retried := false
+		ctx = api.WithRetryObserver(ctx, func(retry api.Retry) {
+			retried = true
+			if session != nil {
+				session.Send(RetryingMsg{retry: &retry})
+			}
+		})
+
+		msg := fn(ctx)
+		err, _ := msg.(error)
+		telemetry.End(span, err)
+		if retried && session != nil {
+			session.Send(RetryingMsg{})
+		}
+		return msg
+	}
+}
+
+func (m model) RetryingView() string {
+	retry := m.state.retrying
+	return fmt.Sprintf("connection trouble, retrying (%d/%d)...", retry.Attempt+1, retry.MaxAttempts)
+}
@@ -3,6 +3,8 @@ package validate
 import (
 	"fmt"
 	"net/mail"
+	"strings"
+	"time"
 )
 
 type ErrorHandler func(str string) error
@@ -115,3 +117,47 @@ func Compose(input ...ErrorHandler) ErrorHandler {
 		return nil
 	}
 }
+
+func IsCode(name string) ErrorHandler {
+	return func(str string) error {
+		for _, c := range str {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return fmt.Errorf("%s can only have letters, digits and dashes", name)
+			}
+		}
+		return nil
+	}
+}
+
+func IsVATID(name string) ErrorHandler {
+	return func(str string) error {
+		id := strings.ToUpper(strings.ReplaceAll(str, " ", ""))
+		if id == "" {
+			return nil
+		}
+		if len(id) < 4 || len(id) > 14 {
+			return fmt.Errorf("%s must be 4 to 14 characters", name)
+		}
+		for i, c := range id {
+			if i < 2 && !(c >= 'A' && c <= 'Z') {
+				return fmt.Errorf("%s must start with a country code, e.g. DE", name)
+			}
+			if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z') {
+				return fmt.Errorf("%s can only have letters and digits", name)
+			}
+		}
+		return nil
+	}
+}
+
+func IsDate(name string) ErrorHandler {
+	return func(str string) error {
+		if str == "" {
+			return nil
+		}
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			return fmt.Errorf("%s must be a date like 2006-01-02", name)
+		}
+		return nil
+	}
+}